@@ -0,0 +1,128 @@
+// Package adjust prepares grayscale images for thermal printing.
+//
+// Thermal paper has a narrow tonal range and strong dot gain: every
+// dot bleeds into its neighbours, so midtones print much darker than
+// they are on screen and photos come out muddy. The filters here are
+// applied after scaling and before dithering to compensate.
+package adjust
+
+import (
+	"image"
+	"math"
+)
+
+// Filter modifies a grayscale image in place.
+type Filter interface {
+	Apply(g *image.Gray)
+}
+
+// FilterFunc adapts a function to Filter.
+type FilterFunc func(g *image.Gray)
+
+func (f FilterFunc) Apply(g *image.Gray) {
+	f(g)
+}
+
+// Pipeline applies its filters in order.
+type Pipeline []Filter
+
+func (p Pipeline) Apply(g *image.Gray) {
+	for _, f := range p {
+		f.Apply(g)
+	}
+}
+
+// Curve maps every input level to an output level.
+type Curve [256]uint8
+
+func (c *Curve) Apply(g *image.Gray) {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	for y := 0; y < h; y++ {
+		row := g.Pix[y*g.Stride : y*g.Stride+w]
+		for x, v := range row {
+			row[x] = c[v]
+		}
+	}
+}
+
+// curve builds a Curve from f, which maps 0-1 (black to white) to 0-1.
+func curve(f func(v float64) float64) *Curve {
+	var c Curve
+	for i := range c {
+		c[i] = level(f(float64(i) / 255))
+	}
+	return &c
+}
+
+func level(v float64) uint8 {
+	return uint8(math.Max(0, math.Min(255, math.Round(v*255))))
+}
+
+// Gamma applies a gamma curve. Values above 1 lighten the midtones,
+// values below 1 darken them; black and white are unchanged.
+func Gamma(gamma float64) *Curve {
+	return curve(func(v float64) float64 {
+		return math.Pow(v, 1/gamma)
+	})
+}
+
+// ContrastBrightness scales contrast around mid gray by contrast (1
+// leaves it unchanged) and then shifts every level by brightness, a
+// fraction of the full range from -1 to 1.
+func ContrastBrightness(contrast, brightness float64) *Curve {
+	return curve(func(v float64) float64 {
+		return (v-0.5)*contrast + 0.5 + brightness
+	})
+}
+
+// DotGain compensates for dots printing larger than their nominal
+// size. gain is how much darker than intended a 50% tone prints, for
+// example 0.2 when 50% comes out as 70%; it is limited to 0.25. The
+// curve lightens the image so that, after the gain, tones print as
+// intended.
+func DotGain(gain float64) *Curve {
+	k := math.Max(0, math.Min(0.25, gain))
+	if k == 0 {
+		return curve(func(v float64) float64 { return v })
+	}
+	// Printed darkness of a nominal darkness d is d + 4k·d(1-d);
+	// solve for the d that prints as the wanted darkness t.
+	return curve(func(v float64) float64 {
+		t := 1 - v
+		b := 1 + 4*k
+		d := (b - math.Sqrt(b*b-16*k*t)) / (8 * k)
+		return 1 - d
+	})
+}
+
+// AutoLevels stretches each image so that its darkest pixels become
+// black and its lightest white. clip is the fraction of pixels at each
+// end that are ignored when finding the range, so a few stray pixels do
+// not defeat the stretch; 0.005 is a good start.
+func AutoLevels(clip float64) Filter {
+	return FilterFunc(func(g *image.Gray) {
+		w, h := g.Rect.Dx(), g.Rect.Dy()
+		var hist [256]int
+		for y := 0; y < h; y++ {
+			for _, v := range g.Pix[y*g.Stride : y*g.Stride+w] {
+				hist[v]++
+			}
+		}
+		skip := int(clip * float64(w*h))
+		lo, hi := 0, 255
+		for n := hist[0]; lo < 255 && n <= skip; n += hist[lo] {
+			lo++
+		}
+		for n := hist[255]; hi > 0 && n <= skip; n += hist[hi] {
+			hi--
+		}
+		if hi <= lo {
+			return
+		}
+		var c Curve
+		for i := range c {
+			c[i] = level(float64(i-lo) / float64(hi-lo))
+		}
+		c.Apply(g)
+	})
+}
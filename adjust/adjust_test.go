@@ -0,0 +1,152 @@
+package adjust
+
+import (
+	"image"
+	"math"
+	"testing"
+)
+
+func gray(w, h int, pix ...uint8) *image.Gray {
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	copy(g.Pix, pix)
+	return g
+}
+
+func TestGamma(t *testing.T) {
+	for _, gamma := range []float64{0.5, 1, 2.2} {
+		c := Gamma(gamma)
+		if c[0] != 0 || c[255] != 255 {
+			t.Errorf("Gamma(%v) maps black to %d and white to %d", gamma, c[0], c[255])
+		}
+	}
+	for i, v := range Gamma(1) {
+		if int(v) != i {
+			t.Fatalf("Gamma(1) maps %d to %d", i, v)
+		}
+	}
+	if v := Gamma(2)[64]; v != 128 {
+		t.Errorf("Gamma(2) maps 64 to %d, want 128", v)
+	}
+	if v := Gamma(0.5)[128]; v != 64 {
+		t.Errorf("Gamma(0.5) maps 128 to %d, want 64", v)
+	}
+}
+
+func TestContrastBrightness(t *testing.T) {
+	tests := []struct {
+		contrast, brightness float64
+		in, want             []uint8
+	}{
+		{1, 0, []uint8{0, 64, 128, 255}, []uint8{0, 64, 128, 255}},
+		{0, 0, []uint8{0, 64, 128, 255}, []uint8{128, 128, 128, 128}},
+		{2, 0, []uint8{0, 32, 192, 255}, []uint8{0, 0, 255, 255}},
+		{1, 0.2, []uint8{0, 128, 230, 255}, []uint8{51, 179, 255, 255}},
+		{1, -1, []uint8{0, 128, 255}, []uint8{0, 0, 0}},
+	}
+	for _, tt := range tests {
+		c := ContrastBrightness(tt.contrast, tt.brightness)
+		for i, v := range tt.in {
+			if c[v] != tt.want[i] {
+				t.Errorf("ContrastBrightness(%v, %v) maps %d to %d, want %d", tt.contrast, tt.brightness, v, c[v], tt.want[i])
+			}
+		}
+	}
+}
+
+func TestDotGain(t *testing.T) {
+	for _, gain := range []float64{0.05, 0.2, 0.25} {
+		c := DotGain(gain)
+		for i, v := range c {
+			// Darkness printed for the corrected level.
+			d := 1 - float64(v)/255
+			printed := d + 4*gain*d*(1-d)
+			if want := 1 - float64(i)/255; math.Abs(printed-want) > 1.5/255 {
+				t.Fatalf("DotGain(%v): %d prints as darkness %.3f, want %.3f", gain, i, printed, want)
+			}
+		}
+		if c[0] != 0 || c[255] != 255 {
+			t.Errorf("DotGain(%v) maps black to %d and white to %d", gain, c[0], c[255])
+		}
+		if c[128] <= 128 {
+			t.Errorf("DotGain(%v) does not lighten the midtones", gain)
+		}
+	}
+	if *DotGain(1) != *DotGain(0.25) {
+		t.Error("DotGain(1) is not limited to 0.25")
+	}
+	for _, gain := range []float64{0, -1} {
+		for i, v := range DotGain(gain) {
+			if int(v) != i {
+				t.Fatalf("DotGain(%v) maps %d to %d", gain, i, v)
+			}
+		}
+	}
+}
+
+func TestAutoLevels(t *testing.T) {
+	// 10 black and 10 white outliers around 980 pixels from 50 to 200.
+	g := image.NewGray(image.Rect(0, 0, 100, 10))
+	for i := range g.Pix {
+		switch {
+		case i < 10:
+			g.Pix[i] = 0
+		case i >= 990:
+			g.Pix[i] = 255
+		default:
+			g.Pix[i] = uint8(50 + (i-10)*150/979)
+		}
+	}
+	orig := append([]uint8(nil), g.Pix...)
+
+	// Clipping 1% at each end ignores the outliers and stretches the
+	// rest to the full range.
+	AutoLevels(0.01).Apply(g)
+	if g.Pix[10] != 0 || g.Pix[989] != 255 {
+		t.Errorf("AutoLevels(0.01) maps 50 to %d and 200 to %d, want 0 and 255", g.Pix[10], g.Pix[989])
+	}
+	if g.Pix[0] != 0 || g.Pix[999] != 255 {
+		t.Errorf("AutoLevels(0.01) moved the outliers to %d and %d", g.Pix[0], g.Pix[999])
+	}
+
+	// Clipping 0.5% keeps them, so the range is already full.
+	copy(g.Pix, orig)
+	AutoLevels(0.005).Apply(g)
+	if string(g.Pix) != string(orig) {
+		t.Error("AutoLevels(0.005) changed an image that spans the full range")
+	}
+
+	for _, v := range []uint8{0, 100, 255} {
+		g := gray(4, 4)
+		for i := range g.Pix {
+			g.Pix[i] = v
+		}
+		AutoLevels(0.01).Apply(g)
+		for _, p := range g.Pix {
+			if p != v {
+				t.Fatalf("AutoLevels changed a flat %d image to %d", v, p)
+			}
+		}
+	}
+
+	g = gray(4, 1, 100, 150, 150, 100)
+	AutoLevels(0).Apply(g)
+	if want := []uint8{0, 255, 255, 0}; string(g.Pix) != string(want) {
+		t.Errorf("AutoLevels(0) on two levels = %v, want %v", g.Pix, want)
+	}
+}
+
+func TestPipeline(t *testing.T) {
+	outer := gray(4, 1, 10, 20, 30, 40)
+	g := outer.SubImage(image.Rect(1, 0, 3, 1)).(*image.Gray)
+	add := func(n uint8) Filter {
+		return FilterFunc(func(g *image.Gray) {
+			for x := g.Rect.Min.X; x < g.Rect.Max.X; x++ {
+				g.Pix[g.PixOffset(x, 0)] = g.Pix[g.PixOffset(x, 0)]*2 + n
+			}
+		})
+	}
+	Pipeline{add(1), add(0), Gamma(1)}.Apply(g)
+	if want := []uint8{10, 82, 122, 40}; string(outer.Pix) != string(want) {
+		t.Errorf("Pipeline = %v, want %v", outer.Pix, want)
+	}
+}
@@ -0,0 +1,87 @@
+package adjust
+
+import (
+	"image"
+	"math"
+)
+
+// UnsharpMask sharpens edges, which survive dithering much better than
+// soft gradients. radius is the standard deviation of the blur in
+// pixels, amount how strongly the difference from the blur is added
+// back (0.5 to 1 is typical), and differences smaller than threshold
+// are left alone so that flat areas do not pick up noise.
+func UnsharpMask(radius, amount float64, threshold uint8) Filter {
+	kernel := gaussian(radius)
+	return FilterFunc(func(g *image.Gray) {
+		w, h := g.Rect.Dx(), g.Rect.Dy()
+		if w == 0 || h == 0 || len(kernel) == 1 {
+			return
+		}
+		soft := blur(g, kernel)
+		for y := 0; y < h; y++ {
+			row := g.Pix[y*g.Stride : y*g.Stride+w]
+			for x, v := range row {
+				diff := float64(v) - soft[y*w+x]
+				if math.Abs(diff) < float64(threshold) {
+					continue
+				}
+				row[x] = uint8(math.Max(0, math.Min(255, math.Round(float64(v)+amount*diff))))
+			}
+		}
+	})
+}
+
+// gaussian returns a normalised kernel of half width 3σ.
+func gaussian(sigma float64) []float64 {
+	r := int(math.Ceil(3 * sigma))
+	if sigma <= 0 || r < 1 {
+		return []float64{1}
+	}
+	k := make([]float64, 2*r+1)
+	sum := 0.0
+	for i := range k {
+		d := float64(i - r)
+		k[i] = math.Exp(-d * d / (2 * sigma * sigma))
+		sum += k[i]
+	}
+	for i := range k {
+		k[i] /= sum
+	}
+	return k
+}
+
+// blur applies kernel along both axes, repeating the edge pixels.
+func blur(g *image.Gray, kernel []float64) []float64 {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	r := len(kernel) / 2
+	clamp := func(i, n int) int {
+		if i < 0 {
+			return 0
+		}
+		if i >= n {
+			return n - 1
+		}
+		return i
+	}
+	tmp := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 0.0
+			for i, k := range kernel {
+				v += k * float64(g.Pix[y*g.Stride+clamp(x+i-r, w)])
+			}
+			tmp[y*w+x] = v
+		}
+	}
+	out := make([]float64, w*h)
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			v := 0.0
+			for i, k := range kernel {
+				v += k * tmp[clamp(y+i-r, h)*w+x]
+			}
+			out[y*w+x] = v
+		}
+	}
+	return out
+}
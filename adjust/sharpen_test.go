@@ -0,0 +1,85 @@
+package adjust
+
+import (
+	"math"
+	"testing"
+)
+
+// edge returns a 16x1 image, lo on the left half and hi on the right.
+func edge(lo, hi uint8) []uint8 {
+	pix := make([]uint8, 16)
+	for i := range pix {
+		pix[i] = lo
+		if i >= 8 {
+			pix[i] = hi
+		}
+	}
+	return pix
+}
+
+func TestUnsharpMaskThreshold(t *testing.T) {
+	// The blur of a 40 level step differs from it by at most 20.
+	g := gray(16, 1, edge(100, 140)...)
+	UnsharpMask(1, 1, 21).Apply(g)
+	if string(g.Pix) != string(edge(100, 140)) {
+		t.Errorf("edge below the threshold changed: %v", g.Pix)
+	}
+
+	g = gray(16, 1, edge(100, 140)...)
+	UnsharpMask(1, 1, 2).Apply(g)
+	if g.Pix[7] >= 100 || g.Pix[8] <= 140 {
+		t.Errorf("edge above the threshold not sharpened: %v", g.Pix)
+	}
+	// Flat areas away from the edge are left alone.
+	if g.Pix[0] != 100 || g.Pix[15] != 140 {
+		t.Errorf("flat areas changed: %v", g.Pix)
+	}
+	// The result is symmetric about the edge.
+	for i := 0; i < 8; i++ {
+		if d := int(g.Pix[i]) - 100 + int(g.Pix[15-i]) - 140; d < -1 || d > 1 {
+			t.Errorf("sharpening is lopsided: %v", g.Pix)
+			break
+		}
+	}
+
+	// A single noisy pixel differs from its blur by less than 3.
+	noise := make([]uint8, 25)
+	for i := range noise {
+		noise[i] = 128
+	}
+	noise[12] = 131
+	g = gray(5, 5, noise...)
+	UnsharpMask(1, 1, 3).Apply(g)
+	if string(g.Pix) != string(noise) {
+		t.Errorf("noise below the threshold changed: %v", g.Pix)
+	}
+	UnsharpMask(1, 1, 0).Apply(g)
+	if g.Pix[12] <= 131 {
+		t.Errorf("noise at threshold 0 not sharpened: %v", g.Pix)
+	}
+}
+
+func TestUnsharpMaskNoRadius(t *testing.T) {
+	g := gray(16, 1, edge(0, 255)...)
+	UnsharpMask(0, 1, 0).Apply(g)
+	if string(g.Pix) != string(edge(0, 255)) {
+		t.Errorf("UnsharpMask with radius 0 changed the image: %v", g.Pix)
+	}
+}
+
+func TestGaussian(t *testing.T) {
+	k := gaussian(1)
+	if len(k) != 7 {
+		t.Fatalf("gaussian(1) has %d taps, want 7", len(k))
+	}
+	sum := 0.0
+	for i, v := range k {
+		sum += v
+		if v != k[len(k)-1-i] {
+			t.Errorf("gaussian(1) is not symmetric: %v", k)
+		}
+	}
+	if math.Abs(sum-1) > 1e-12 {
+		t.Errorf("gaussian(1) sums to %v", sum)
+	}
+}
@@ -0,0 +1,235 @@
+package thermalprinter
+
+import (
+	"context"
+	"fmt"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"math"
+	"time"
+)
+
+// bitmapBufferBytes bounds the bitmap data sent in one chunk, so that
+// a chunk fits in the printer's input buffer. The CSN-A2 datasheet
+// does not give its size; this is what the Adafruit library assumes.
+// With flow control or a busy line the printer says when the buffer
+// is full, and chunks are as tall as DC2 * allows.
+const bitmapBufferBytes = 256
+
+// maxBitmapRows is the most rows DC2 * can carry.
+const maxBitmapRows = 255
+
+// PrintBitmap prints a w x h bitmap packed MSB first, (w+7)/8 bytes per
+// row, a set bit being a black dot. Rows wider than the print width are
+// clipped. An error is returned if bitmap is shorter than h rows.
+func (p *Printer) PrintBitmap(w int, h int, bitmap []byte) error {
+	return p.PrintBitmapContext(context.Background(), w, h, bitmap)
+}
+
+// BitmapProgress reports how far a bitmap print has got. It is
+// delivered after every chunk.
+type BitmapProgress struct {
+	Rows       int           // rows sent so far
+	TotalRows  int           // rows in the bitmap
+	Bytes      int           // bitmap bytes sent so far, headers included
+	TotalBytes int           // bitmap bytes to send, headers included
+	Remaining  time.Duration // estimated time until the last row is printed
+}
+
+// SetBitmapProgress registers fn to be called as bitmap chunks are
+// sent. A nil fn disables reporting.
+func (p *Printer) SetBitmapProgress(fn func(BitmapProgress)) {
+	p.bitmapProgress = fn
+}
+
+// PrintBitmapContext is PrintBitmap with cancellation. ctx is checked
+// between chunks only: once a chunk header has been sent the printer
+// expects all of its rows, so the chunk is always completed. If some
+// rows have already been printed the paper is then fed past them before
+// ctx.Err() is returned.
+//
+// The bitmap is sent in chunks sized to the printer's input buffer and
+// each chunk is paced like text: the next one is sent once the printer
+// should have finished with the previous one. With flow control or a
+// busy line every row is written separately, so that the printer can
+// stop the transfer in the middle of a chunk.
+func (p *Printer) PrintBitmapContext(ctx context.Context, w int, h int, bitmap []byte) error {
+	if err := p.printable(); err != nil {
+		return err
+	}
+	rowBytes := (w + 7) / 8 // Round up to next byte boundary
+	rowBytesClipped := rowBytes
+	if maxRowBytes := p.printWidth / 8; rowBytes > maxRowBytes {
+		rowBytesClipped = maxRowBytes
+	}
+	if rowBytesClipped <= 0 || h <= 0 {
+		return nil
+	}
+	if len(bitmap) < h*rowBytes {
+		return fmt.Errorf("thermalprinter: %dx%d bitmap needs %d bytes, got %d", w, h, h*rowBytes, len(bitmap))
+	}
+
+	maxRows := bitmapChunkRows(h, rowBytesClipped, p.flow != nil || p.handshaking)
+	chunks := planBitmap(bitmap, h, rowBytes, rowBytesClipped, maxRows)
+	progress := BitmapProgress{TotalRows: h}
+	remaining := 0.0
+	for _, c := range chunks {
+		progress.TotalBytes += c.size()
+		remaining += p.chunkTime(c)
+	}
+
+	for n, c := range chunks {
+		if n > 0 && ctx.Err() != nil {
+			return p.abortBitmap(ctx)
+		}
+		// The rows of a chunk are separate pieces so that the printer
+		// can hold them back with XOFF or its busy line.
+		var pieces [][]byte
+		if c.blank {
+			pieces = [][]byte{escpos.FeedRows(byte(c.rows))}
+			p.logDebug(ctx, "bitmap feed", "row", c.row, "rows", c.rows)
+		} else {
+			pieces = [][]byte{escpos.BitmapHeader(byte(c.rows), byte(c.width))}
+			for y := c.row; y < c.row+c.rows; y++ {
+				pieces = append(pieces, bitmap[y*rowBytes:y*rowBytes+c.width])
+			}
+			p.logDebug(ctx, "bitmap chunk", "row", c.row, "rows", c.rows, "rowBytes", c.width)
+		}
+
+		if err := p.timeoutWait(ctx); err != nil {
+			if n > 0 && ctx.Err() != nil {
+				return p.abortBitmap(ctx)
+			}
+			return err
+		}
+		// Once the header is out the printer expects every row, so the
+		// chunk itself is not cancelled.
+		d := p.chunkTime(c)
+		if err := p.sendPieces(context.Background(), pieces, d); err != nil {
+			return err
+		}
+
+		if p.bitmapProgress != nil {
+			progress.Rows += c.rows
+			progress.Bytes += c.size()
+			progress.Remaining = seconds(math.Max(remaining, 0))
+			p.bitmapProgress(progress)
+		}
+		remaining -= d
+	}
+	p.prevByte = newlineByte()
+
+	return nil
+}
+
+// abortBitmap feeds the paper past the rows printed so far and returns
+// why the bitmap was cancelled.
+func (p *Printer) abortBitmap(ctx context.Context) error {
+	if err := p.Feed(2); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// chunkTime estimates how long the printer is busy with a chunk: the
+// time to transfer its bytes plus the time to print its rows, or to
+// feed over them for a blank chunk.
+func (p *Printer) chunkTime(c bitmapChunk) float64 {
+	transfer := float64(c.size()) * p.byteTime
+	if c.blank {
+		return transfer + float64(c.rows)*p.dotFeedTime
+	}
+	return transfer + float64(c.rows)*p.dotPrintTime
+}
+
+// bitmapChunkRows picks the rows per chunk for a bitmap with rows of
+// width bytes: as many as fit in the printer's input buffer, or as
+// DC2 * allows when handshake is set, spread evenly so the last chunk
+// is not a sliver.
+func bitmapChunkRows(h, width int, handshake bool) int {
+	limit := maxBitmapRows
+	if !handshake {
+		limit = bitmapBufferBytes / width
+	}
+	if limit > maxBitmapRows {
+		limit = maxBitmapRows
+	}
+	if limit < 1 {
+		limit = 1
+	}
+	chunks := (h + limit - 1) / limit
+	return (h + chunks - 1) / chunks
+}
+
+// bitmapChunk is one command of a bitmap print: either up to 255 rows
+// of dots sent with DC2 *, or a run of blank rows fed over with ESC J.
+type bitmapChunk struct {
+	row   int  // first bitmap row
+	rows  int  // number of rows
+	blank bool // feed instead of printing
+	width int  // bytes sent per row
+}
+
+// size returns the number of bytes sent for the chunk.
+func (c bitmapChunk) size() int {
+	if c.blank {
+		return len(escpos.FeedRows(0))
+	}
+	return len(escpos.BitmapHeader(0, 0)) + c.rows*c.width
+}
+
+// planBitmap splits a bitmap into chunks of at most maxRows rows.
+// Runs of blank rows that cost more to send than to feed over are
+// skipped with paper feeds, and each chunk only sends its rows up to
+// the last byte that has a dot in any of them: DC2 * prints rows from
+// the left edge, so trailing white bytes can be dropped but leading
+// ones cannot.
+func planBitmap(bitmap []byte, h, rowBytes, clipped, maxRows int) []bitmapChunk {
+	// used[y] is the number of bytes of row y up to its last dot and
+	// blank[y] the number of blank rows starting at y.
+	used := make([]int, h)
+	blank := make([]int, h+1)
+	for y := h - 1; y >= 0; y-- {
+		row := bitmap[y*rowBytes : y*rowBytes+clipped]
+		for n := len(row); n > 0; n-- {
+			if row[n-1] != 0 {
+				used[y] = n
+				break
+			}
+		}
+		if used[y] == 0 {
+			blank[y] = blank[y+1] + 1
+		}
+	}
+	// A feed and the header that restarts the bitmap after it cost
+	// this much; shorter blank runs are cheaper to send as dots.
+	restart := len(escpos.FeedRows(0)) + len(escpos.BitmapHeader(0, 0))
+	minBlank := restart/clipped + 1
+
+	var chunks []bitmapChunk
+	for y := 0; y < h; {
+		if blank[y] >= minBlank {
+			n := blank[y]
+			if n > maxBitmapRows {
+				n = maxBitmapRows
+			}
+			chunks = append(chunks, bitmapChunk{row: y, rows: n, blank: true})
+			y += n
+			continue
+		}
+		c := bitmapChunk{row: y}
+		for y < h && c.rows < maxRows && (c.rows == 0 || blank[y] < minBlank) {
+			if used[y] > c.width {
+				c.width = used[y]
+			}
+			c.rows++
+			y++
+		}
+		if c.width == 0 {
+			// Too few blank rows to be worth a feed on their own, but
+			// a bitmap with no bytes would not move the paper at all.
+			c.blank = true
+		}
+		chunks = append(chunks, c)
+	}
+	return chunks
+}
@@ -0,0 +1,460 @@
+package thermalprinter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"github.com/koyachi/go-thermalprinter/emulator"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"image"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestPrintBitmapShort(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	// 16 dots wide is 2 bytes a row; 3 rows need 6.
+	if err := p.PrintBitmap(16, 3, make([]byte, 5)); err == nil {
+		t.Fatal("PrintBitmap with a short bitmap succeeded")
+	}
+	if len(r.writes) != 0 {
+		t.Errorf("sent %q for a short bitmap", r.writes[0].data)
+	}
+}
+
+func TestPrintBitmap(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	bitmap := []byte{0xff, 0x00, 0x80, 0x00, 0x00, 0x00}
+	if err := p.PrintBitmap(16, 3, bitmap); err != nil {
+		t.Fatal(err)
+	}
+	// Trailing white bytes are not sent.
+	want := []byte{0x12, 0x2a, 3, 1, 0xff, 0x80, 0x00}
+	if len(r.writes) != 1 || !bytes.Equal([]byte(r.writes[0].data), want) {
+		t.Errorf("writes = %v, want % x", r.writes, want)
+	}
+}
+
+// xoffTransport is an emulator with a return path. Once xoffAt bytes
+// have arrived it sends XOFF, and XON a little later; overrun counts
+// the bytes that arrive in between.
+type xoffTransport struct {
+	*emulator.Emulator
+	replies chan byte
+	done    chan struct{}
+	flow    *flow
+
+	mu       sync.Mutex
+	xoffAt   int
+	received int
+	paused   bool
+	overrun  int
+	resumed  sync.WaitGroup
+}
+
+func newXoffTransport() *xoffTransport {
+	return &xoffTransport{
+		Emulator: emulator.New(),
+		replies:  make(chan byte, 64),
+		done:     make(chan struct{}),
+	}
+}
+
+// arm starts counting bytes towards an XOFF after n bytes.
+func (x *xoffTransport) arm(n int) {
+	x.mu.Lock()
+	defer x.mu.Unlock()
+	x.xoffAt, x.received = n, 0
+}
+
+func (x *xoffTransport) Write(data []byte) (int, error) {
+	x.mu.Lock()
+	if x.paused {
+		x.overrun += len(data)
+	}
+	x.received += len(data)
+	trigger := x.xoffAt > 0 && x.received > x.xoffAt
+	if trigger {
+		x.overrun += x.received - x.xoffAt
+		x.xoffAt = 0
+		x.paused = true
+	}
+	x.mu.Unlock()
+
+	n, err := x.Emulator.Write(data)
+	// Pass status replies on behind any flow control bytes.
+	buf := make([]byte, 16)
+	for {
+		m, rerr := x.Emulator.Read(buf)
+		for _, b := range buf[:m] {
+			x.replies <- b
+		}
+		if rerr != nil {
+			break
+		}
+	}
+	if trigger {
+		x.replies <- xoff
+		// Unlike on a real line, the XOFF is seen before the next
+		// write starts.
+		for !x.flowPaused() {
+			time.Sleep(time.Millisecond)
+		}
+		x.resumed.Add(1)
+		go func() {
+			defer x.resumed.Done()
+			time.Sleep(20 * time.Millisecond)
+			x.mu.Lock()
+			x.paused = false
+			x.mu.Unlock()
+			x.replies <- xon
+		}()
+	}
+	return n, err
+}
+
+func (x *xoffTransport) flowPaused() bool {
+	x.flow.mu.Lock()
+	defer x.flow.mu.Unlock()
+	return x.flow.paused
+}
+
+func (x *xoffTransport) Read(data []byte) (int, error) {
+	select {
+	case b := <-x.replies:
+		data[0] = b
+		return 1, nil
+	case <-x.done:
+		return 0, io.EOF
+	}
+}
+
+func (x *xoffTransport) Close() error {
+	x.resumed.Wait()
+	close(x.done)
+	return x.Emulator.Close()
+}
+
+func newFlowPrinter(t *testing.T) (*Printer, *xoffTransport) {
+	t.Helper()
+	x := newXoffTransport()
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	p, err := NewPrinterWithTransport(x, 19200, 0, WithClock(clock), WithFlowControl())
+	if err != nil {
+		t.Fatal(err)
+	}
+	x.flow = p.flow
+	t.Cleanup(func() { p.Close() })
+	return p, x
+}
+
+func TestPrintBitmapHonorsXOFFMidChunk(t *testing.T) {
+	const w, h = 384, 24
+	bitmap := bytes.Repeat([]byte{0xaa}, w/8*h)
+
+	p, x := newFlowPrinter(t)
+	x.arm(100)
+	if err := p.PrintBitmap(w, h, bitmap); err != nil {
+		t.Fatal(err)
+	}
+	x.resumed.Wait()
+	if x.xoffAt != 0 {
+		t.Fatal("the bitmap never reached the XOFF")
+	}
+	// The row being written when XOFF arrives can still get through,
+	// nothing after it.
+	if x.overrun > w/8 {
+		t.Errorf("%d bytes sent after XOFF, want at most one row of %d", x.overrun, w/8)
+	}
+
+	want, r, _ := newTestPrinter(t)
+	if err := want.PrintBitmap(w, h, bitmap); err != nil {
+		t.Fatal(err)
+	}
+	if !sameImage(x.Image(), r.Image()) {
+		t.Error("bitmap printed with flow control differs from the one printed without")
+	}
+	if d := x.Diagnostics(); len(d) != 0 {
+		t.Errorf("emulator diagnostics: %v", d)
+	}
+}
+
+func TestPrintBitmapChecksBusyLinePerRow(t *testing.T) {
+	p, _, _ := newTestPrinter(t)
+	checks := 0
+	p.busyLine = BusyLineFunc(func() (bool, error) {
+		checks++
+		return false, nil
+	})
+	// Once the busy line has been read the whole bitmap is one chunk.
+	if err := p.Feed(1); err != nil {
+		t.Fatal(err)
+	}
+	checks = 0
+	if err := p.PrintBitmap(384, 24, bytes.Repeat([]byte{0xff}, 48*24)); err != nil {
+		t.Fatal(err)
+	}
+	// The header, then every row.
+	if checks != 1+24 {
+		t.Errorf("busy line checked %d times, want %d", checks, 1+24)
+	}
+}
+
+func TestBitmapChunkRows(t *testing.T) {
+	tests := []struct {
+		h, width  int
+		handshake bool
+		want      int
+	}{
+		{24, 48, false, 5},   // 256 bytes hold 5 full rows
+		{7, 48, false, 4},    // 2 chunks of 4 and 3, not 5 and 2
+		{2000, 48, false, 5}, // tall images keep the buffer limit
+		{10, 1, false, 10},   // one chunk
+		{300, 1, false, 150}, // at most 255 rows a chunk
+		{3, 300, false, 1},   // rows wider than the buffer
+		{600, 48, true, 200}, // the printer paces itself
+	}
+	for _, tt := range tests {
+		if got := bitmapChunkRows(tt.h, tt.width, tt.handshake); got != tt.want {
+			t.Errorf("bitmapChunkRows(%d, %d, %v) = %d, want %d", tt.h, tt.width, tt.handshake, got, tt.want)
+		}
+	}
+}
+
+// rows builds a bitmap of rowBytes wide rows, each given as the bytes
+// at its start; the rest is white.
+func rows(rowBytes int, starts ...[]byte) []byte {
+	bitmap := make([]byte, 0, rowBytes*len(starts))
+	for _, s := range starts {
+		row := make([]byte, rowBytes)
+		copy(row, s)
+		bitmap = append(bitmap, row...)
+	}
+	return bitmap
+}
+
+func TestPlanBitmap(t *testing.T) {
+	black := bytes.Repeat([]byte{0xff}, 60)
+	white := []byte(nil)
+	tests := []struct {
+		name              string
+		bitmap            []byte
+		h, rowBytes, clip int
+		maxRows           int
+		want              []bitmapChunk
+	}{
+		{
+			"tall", rows(48, black, black, black, black, black, black, black, black, black, black, black, black),
+			12, 48, 48, 4,
+			[]bitmapChunk{{0, 4, false, 48}, {4, 4, false, 48}, {8, 4, false, 48}},
+		},
+		{
+			"wide rows are clipped", rows(60, black, black),
+			2, 60, 48, 5,
+			[]bitmapChunk{{0, 2, false, 48}},
+		},
+		{
+			"trailing white bytes", rows(48, []byte{0x80}, []byte{0, 0, 1}),
+			2, 48, 48, 5,
+			[]bitmapChunk{{0, 2, false, 3}},
+		},
+		{
+			// A feed and a new header cost 7 bytes, more than one
+			// full row: any blank row is fed over.
+			"blank row fed", rows(48, black, white, black),
+			3, 48, 48, 5,
+			[]bitmapChunk{{0, 1, false, 48}, {1, 1, true, 0}, {2, 1, false, 48}},
+		},
+		{
+			// With 2 byte rows a blank run must be 4 rows to be fed.
+			"short blank run sent", rows(2, black, white, white, white, black),
+			5, 2, 2, 5,
+			[]bitmapChunk{{0, 5, false, 2}},
+		},
+		{
+			"long blank run fed", rows(2, black, white, white, white, white, black),
+			6, 2, 2, 5,
+			[]bitmapChunk{{0, 1, false, 2}, {1, 4, true, 0}, {5, 1, false, 2}},
+		},
+		{
+			"short blank bitmap", rows(2, white, white),
+			2, 2, 2, 5,
+			[]bitmapChunk{{0, 2, true, 0}},
+		},
+		{
+			"blank run over 255 rows", make([]byte, 300),
+			300, 1, 1, 255,
+			[]bitmapChunk{{0, 255, true, 0}, {255, 45, true, 0}},
+		},
+	}
+	for _, tt := range tests {
+		got := planBitmap(tt.bitmap, tt.h, tt.rowBytes, tt.clip, tt.maxRows)
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: planBitmap = %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: planBitmap = %+v, want %+v", tt.name, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestChunkTime(t *testing.T) {
+	p, _, _ := newTestPrinter(t)
+	dots := bitmapChunk{rows: 5, width: 48}
+	want := float64(4+5*48)*p.byteTime + 5*p.dotPrintTime
+	assertDuration(t, "dots", seconds(p.chunkTime(dots)), seconds(want))
+
+	feed := bitmapChunk{rows: 10, blank: true}
+	want = 3*p.byteTime + 10*p.dotFeedTime
+	assertDuration(t, "feed", seconds(p.chunkTime(feed)), seconds(want))
+}
+
+func TestPrintBitmapPacing(t *testing.T) {
+	p, r, clock := newTestPrinter(t)
+	if err := p.timeoutWait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	start := clock.Now()
+	bitmap := rows(48, bytes.Repeat([]byte{0xff}, 48), nil, []byte{0xff})
+	if err := p.PrintBitmap(384, 3, bitmap); err != nil {
+		t.Fatal(err)
+	}
+	chunks := []bitmapChunk{{0, 1, false, 48}, {1, 1, true, 0}, {2, 1, false, 1}}
+	if len(r.writes) != len(chunks) {
+		t.Fatalf("got %d writes, want %d", len(r.writes), len(chunks))
+	}
+	at := 0.0
+	for i, c := range chunks {
+		assertDuration(t, fmt.Sprintf("chunk %d", i), r.writes[i].at.Sub(start), seconds(at))
+		at += p.chunkTime(c)
+	}
+	if err := p.timeoutWait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	assertDuration(t, "end", clock.Now().Sub(start), seconds(at))
+}
+
+func TestPrintBitmapProgress(t *testing.T) {
+	p, _, _ := newTestPrinter(t)
+	var got []BitmapProgress
+	p.SetBitmapProgress(func(pr BitmapProgress) { got = append(got, pr) })
+	if err := p.PrintBitmap(384, 24, bytes.Repeat([]byte{0xff}, 48*24)); err != nil {
+		t.Fatal(err)
+	}
+
+	// 24 rows of 48 bytes go in chunks of 5 rows, the last of 4.
+	chunks := []bitmapChunk{{0, 5, false, 48}, {5, 5, false, 48}, {10, 5, false, 48}, {15, 5, false, 48}, {20, 4, false, 48}}
+	if len(got) != len(chunks) {
+		t.Fatalf("progress reported %d times, want %d", len(got), len(chunks))
+	}
+	total, remaining := 0, 0.0
+	for _, c := range chunks {
+		total += c.size()
+		remaining += p.chunkTime(c)
+	}
+	rows, sent := 0, 0
+	for i, c := range chunks {
+		rows += c.rows
+		sent += c.size()
+		pr := got[i]
+		if pr.Rows != rows || pr.TotalRows != 24 || pr.Bytes != sent || pr.TotalBytes != total {
+			t.Errorf("progress %d = %+v, want %d/24 rows, %d/%d bytes", i, pr, rows, sent, total)
+		}
+		// What is left includes the chunk just sent, which is still
+		// being printed.
+		assertDuration(t, fmt.Sprintf("progress %d remaining", i), pr.Remaining, seconds(remaining))
+		remaining -= p.chunkTime(c)
+	}
+	if last := got[len(got)-1]; last.Bytes != last.TotalBytes || last.Rows != last.TotalRows {
+		t.Errorf("last progress %+v is not complete", last)
+	}
+
+	// Reporting stops when the callback is removed.
+	p.SetBitmapProgress(nil)
+	got = nil
+	if err := p.PrintBitmap(8, 1, []byte{0xff}); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 0 {
+		t.Errorf("progress reported %v after SetBitmapProgress(nil)", got)
+	}
+}
+
+func TestPrintBitmapCancel(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	// Cancel partway through the first chunk's rows, once the busy
+	// line has been seen working.
+	checks := 0
+	p.busyLine = BusyLineFunc(func() (bool, error) {
+		if checks++; checks == 10 {
+			cancel()
+		}
+		return false, nil
+	})
+	if err := p.Feed(1); err != nil {
+		t.Fatal(err)
+	}
+	checks = 0
+	r.writes = nil
+	var progress []BitmapProgress
+	p.SetBitmapProgress(func(pr BitmapProgress) { progress = append(progress, pr) })
+
+	// With the busy line working, 300 rows are 2 chunks of 150.
+	err := p.PrintBitmapContext(ctx, 384, 300, bytes.Repeat([]byte{0xff}, 48*300))
+	if err != context.Canceled {
+		t.Fatalf("PrintBitmapContext = %v, want context.Canceled", err)
+	}
+	if len(progress) != 1 || progress[0].Rows != 150 {
+		t.Errorf("progress = %+v, want the first chunk of 150 rows", progress)
+	}
+
+	// The whole first chunk, then Feed(2), then nothing.
+	var sent []byte
+	for _, w := range r.writes {
+		sent = append(sent, w.data...)
+	}
+	want := append(escpos.BitmapHeader(150, 48), bytes.Repeat([]byte{0xff}, 48*150)...)
+	want = append(want, "\n\n"...)
+	if !bytes.Equal(sent, want) {
+		t.Errorf("sent %d bytes ending % x, want the first chunk and two line feeds", len(sent), sent[len(sent)-4:])
+	}
+	if d := r.Diagnostics(); len(d) != 0 {
+		t.Errorf("emulator diagnostics: %v", d)
+	}
+}
+
+func TestPrintBitmapCancelledBeforeStart(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := p.PrintBitmapContext(ctx, 8, 1, []byte{0xff}); err != context.Canceled {
+		t.Errorf("PrintBitmapContext = %v, want context.Canceled", err)
+	}
+	// Nothing was printed, so there is nothing to feed past.
+	if len(r.writes) != 0 {
+		t.Errorf("sent %v", r.writes)
+	}
+}
+
+func sameImage(a, b image.Image) bool {
+	if a.Bounds() != b.Bounds() {
+		return false
+	}
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if a.At(x, y) != b.At(x, y) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,124 @@
+package thermalprinter
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+)
+
+// CaptureTransport records every byte sent to the printer, for example
+// into a .escpos file that can be attached to a bug report or replayed.
+// It tees to an underlying transport, or only records when that is nil
+// (a dry run).
+type CaptureTransport struct {
+	t       Transport
+	capture io.Writer
+	timing  io.Writer
+	start   time.Time
+	offset  int64
+	files   []*os.File
+}
+
+// NewCaptureTransport records the data written through t into capture.
+// When timing is not nil a line "seconds offset length" is logged
+// there for every write, seconds counted from the first write.
+func NewCaptureTransport(t Transport, capture io.Writer, timing io.Writer) *CaptureTransport {
+	return &CaptureTransport{t: t, capture: capture, timing: timing}
+}
+
+// CreateCapture records the data written through t into a new file at
+// path, and the timing log into path+".timing" when withTiming is set.
+// The files are closed with the transport.
+func CreateCapture(t Transport, path string, withTiming bool) (*CaptureTransport, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	c := NewCaptureTransport(t, f, nil)
+	c.files = append(c.files, f)
+	if withTiming {
+		tf, err := os.Create(path + ".timing")
+		if err != nil {
+			f.Close()
+			return nil, err
+		}
+		c.timing = tf
+		c.files = append(c.files, tf)
+	}
+	return c, nil
+}
+
+func (c *CaptureTransport) Write(data []byte) (int, error) {
+	n := len(data)
+	var err error
+	if c.t != nil {
+		n, err = c.t.Write(data)
+	}
+	if n == 0 {
+		return n, err
+	}
+
+	now := time.Now()
+	if c.start.IsZero() {
+		c.start = now
+		if c.timing != nil {
+			fmt.Fprintln(c.timing, "# seconds offset length")
+		}
+	}
+	if _, cerr := c.capture.Write(data[:n]); cerr != nil && err == nil {
+		err = cerr
+	}
+	if c.timing != nil {
+		fmt.Fprintf(c.timing, "%.6f %d %d\n", now.Sub(c.start).Seconds(), c.offset, n)
+	}
+	c.offset += int64(n)
+	return n, err
+}
+
+// Read passes status replies through from the underlying transport.
+func (c *CaptureTransport) Read(data []byte) (int, error) {
+	if r, ok := c.t.(io.Reader); ok {
+		return r.Read(data)
+	}
+	return 0, io.EOF
+}
+
+// readable reports whether Read reaches a transport that can answer;
+// a dry run has nothing to read from.
+func (c *CaptureTransport) readable() bool {
+	_, ok := reader(c.t)
+	return ok
+}
+
+func (c *CaptureTransport) SetWriteDeadline(t time.Time) error {
+	if dw, ok := c.t.(writeDeadliner); ok {
+		return dw.SetWriteDeadline(t)
+	}
+	if c.t == nil {
+		return nil
+	}
+	return os.ErrNoDeadline
+}
+
+func (c *CaptureTransport) SetReadDeadline(t time.Time) error {
+	if rd, ok := c.t.(readDeadliner); ok {
+		return rd.SetReadDeadline(t)
+	}
+	return os.ErrNoDeadline
+}
+
+// Close closes the underlying transport and the files opened by
+// CreateCapture.
+func (c *CaptureTransport) Close() error {
+	var err error
+	if c.t != nil {
+		err = c.t.Close()
+	}
+	for _, f := range c.files {
+		if ferr := f.Close(); err == nil {
+			err = ferr
+		}
+	}
+	return err
+}
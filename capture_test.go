@@ -0,0 +1,44 @@
+package thermalprinter
+
+import (
+	"bytes"
+	"errors"
+	"github.com/koyachi/go-thermalprinter/emulator"
+	"testing"
+)
+
+func TestCaptureDryRunStatus(t *testing.T) {
+	var buf bytes.Buffer
+	p, err := NewPrinterWithTransport(NewCaptureTransport(nil, &buf, nil), virtualBaud, 0, WithClock(&fakeClock{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sent := buf.Len()
+	if _, err := p.Status(); !errors.Is(err, ErrStatusUnsupported) {
+		t.Errorf("Status = %v, want ErrStatusUnsupported", err)
+	}
+	if buf.Len() != sent {
+		t.Errorf("Status sent % x on a dry run", buf.Bytes()[sent:])
+	}
+
+	if _, err := NewPrinterWithTransport(NewCaptureTransport(nil, &buf, nil), virtualBaud, 0, WithClock(&fakeClock{}), WithFlowControl()); !errors.Is(err, ErrFlowControlUnsupported) {
+		t.Errorf("flow control on a dry run: %v, want ErrFlowControlUnsupported", err)
+	}
+}
+
+func TestCaptureStatus(t *testing.T) {
+	var buf bytes.Buffer
+	e := emulator.New()
+	p, err := NewPrinterWithTransport(NewCaptureTransport(e, &buf, nil), virtualBaud, 0, WithClock(&fakeClock{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	e.SetPaper(false, false)
+	st, err := p.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.PaperPresent || !st.Complete {
+		t.Errorf("Status = %+v, want paper out from DLE EOT", st)
+	}
+}
@@ -0,0 +1,66 @@
+// Command escpos-dump prints a readable listing of ESC/POS files.
+//
+//	escpos-dump [-raw] [file...]
+//
+// Standard input is read when no file is given.
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"io"
+	"log"
+	"os"
+)
+
+var raw = flag.Bool("raw", false, "also print the bytes of each command")
+
+func dump(w io.Writer, r io.Reader) error {
+	if !*raw {
+		return escpos.Disassemble(w, r)
+	}
+	s := escpos.NewScanner(r)
+	for s.Scan() {
+		cmd := s.Command()
+		fmt.Fprintln(w, escpos.Listing(cmd))
+		if cmd.Op != escpos.OpText {
+			fmt.Fprintf(w, "          % x\n", cmd.Raw)
+		}
+	}
+	return s.Err()
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("escpos-dump: ")
+	flag.Parse()
+
+	w := bufio.NewWriter(os.Stdout)
+	defer w.Flush()
+
+	if flag.NArg() == 0 {
+		if err := dump(w, os.Stdin); err != nil {
+			w.Flush()
+			log.Fatal(err)
+		}
+		return
+	}
+	for _, name := range flag.Args() {
+		f, err := os.Open(name)
+		if err != nil {
+			w.Flush()
+			log.Fatal(err)
+		}
+		if flag.NArg() > 1 {
+			fmt.Fprintf(w, "%s:\n", name)
+		}
+		err = dump(w, f)
+		f.Close()
+		if err != nil {
+			w.Flush()
+			log.Fatalf("%s: %v", name, err)
+		}
+	}
+}
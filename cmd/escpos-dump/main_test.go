@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestDump(t *testing.T) {
+	stream := new(escpos.Builder).
+		Add(escpos.WakeESC(), escpos.WakeESC(), escpos.Reset()).
+		Add(escpos.PrintMode(escpos.ModeBold)).
+		Println("Bold").
+		Add(escpos.Bitmap(2, 2, []byte{0xf0, 0x0f, 0x0f, 0xf0})).
+		Add([]byte{0x07}, escpos.Barcode(4, "AB"), []byte{escpos.LF}).
+		Bytes()
+	for _, tt := range []struct {
+		raw    bool
+		golden string
+	}{
+		{false, "dump.golden"},
+		{true, "dump-raw.golden"},
+	} {
+		*raw = tt.raw
+		var buf bytes.Buffer
+		if err := dump(&buf, bytes.NewReader(stream)); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join("testdata", tt.golden)
+		if *update {
+			if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		want, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(buf.Bytes(), want) {
+			t.Errorf("-raw=%v: output differs from %s; run go test -update to accept it:\n%s", tt.raw, path, buf.Bytes())
+		}
+	}
+	*raw = false
+}
+
+func TestDumpTruncated(t *testing.T) {
+	var buf bytes.Buffer
+	err := dump(&buf, bytes.NewReader([]byte("ok\n\x1b")))
+	if !errors.Is(err, escpos.ErrTruncated) {
+		t.Errorf("dump = %v, want ErrTruncated", err)
+	}
+	if want := "00000000  text     \"ok\"\n00000002  LF\n"; buf.String() != want {
+		t.Errorf("listing before the error = %q, want %q", buf.String(), want)
+	}
+}
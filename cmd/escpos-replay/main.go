@@ -0,0 +1,71 @@
+// Command escpos-replay prints recorded ESC/POS files, paced for the
+// CSN-A2 so that its buffer does not overflow.
+//
+//	escpos-replay [-device /dev/ttyAMA0 -baud 19200 | -addr host:9100 | -usb /dev/usb/lp0] file...
+//
+// A file named "-" is read from standard input.
+package main
+
+import (
+	"context"
+	"flag"
+	"github.com/koyachi/go-thermalprinter"
+	"io"
+	"log"
+	"os"
+)
+
+var (
+	device  = flag.String("device", "/dev/ttyAMA0", "serial device of the printer")
+	baud    = flag.Int("baud", 19200, "baud rate of the serial device")
+	addr    = flag.String("addr", "", "address of a network printer (host[:port]), instead of -device")
+	usb     = flag.String("usb", "", "usblp device of the printer, instead of -device")
+	timeout = flag.Int("timeout", 5, "seconds a single write may block")
+)
+
+func openPrinter() (*thermalprinter.Printer, error) {
+	switch {
+	case *addr != "":
+		return thermalprinter.NewNetworkPrinter(*addr, *timeout)
+	case *usb != "":
+		return thermalprinter.NewDevicePrinter(*usb, *timeout)
+	default:
+		return thermalprinter.NewPrinter(*device, *baud, *timeout)
+	}
+}
+
+func replay(printer *thermalprinter.Printer, name string) error {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+	return printer.Replay(context.Background(), r)
+}
+
+func main() {
+	log.SetFlags(0)
+	log.SetPrefix("escpos-replay: ")
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	printer, err := openPrinter()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer printer.Close()
+
+	for _, name := range flag.Args() {
+		if err := replay(printer, name); err != nil {
+			printer.Close()
+			log.Fatalf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+package thermalprinter
+
+import (
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DeviceTransport is a Transport over a printer character device such
+// as the Linux usblp node /dev/usb/lp0. Any file or named pipe works
+// as well, which is handy for tests.
+type DeviceTransport struct {
+	f    *os.File
+	path string
+
+	mu           sync.Mutex
+	readDeadline time.Time
+	readOnce     sync.Once
+	incoming     chan deviceRead
+	unread       []byte
+	readErr      error // error that ended the read goroutine
+}
+
+type deviceRead struct {
+	data []byte
+	err  error
+}
+
+// OpenDevice opens the device at path, read-write when possible so
+// that status replies can be read.
+func OpenDevice(path string) (*DeviceTransport, error) {
+	f, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		f, err = os.OpenFile(path, os.O_WRONLY, 0)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &DeviceTransport{f: f, path: path}, nil
+}
+
+// NewDevicePrinter opens the device at path and initializes the
+// printer behind it. timeout is the number of seconds a single write
+// may block.
+func NewDevicePrinter(path string, timeout int, opts ...Option) (*Printer, error) {
+	d, err := OpenDevice(path)
+	if err != nil {
+		return nil, err
+	}
+	p, err := NewPrinterWithTransport(d, virtualBaud, timeout, opts...)
+	if err != nil {
+		d.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+func (d *DeviceTransport) Write(data []byte) (int, error) {
+	return d.f.Write(data)
+}
+
+// Read returns data sent back by the printer. Device reads cannot be
+// interrupted, so they are done by a goroutine and Read only waits
+// for it until the read deadline.
+func (d *DeviceTransport) Read(data []byte) (int, error) {
+	d.readOnce.Do(func() {
+		d.incoming = make(chan deviceRead, 1)
+		go d.readLoop()
+	})
+
+	d.mu.Lock()
+	if len(d.unread) > 0 {
+		n := copy(data, d.unread)
+		d.unread = d.unread[n:]
+		d.mu.Unlock()
+		return n, nil
+	}
+	deadline := d.readDeadline
+	d.mu.Unlock()
+
+	var expired <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case r, ok := <-d.incoming:
+		if !ok {
+			d.mu.Lock()
+			defer d.mu.Unlock()
+			return 0, d.readErr
+		}
+		if r.err != nil {
+			return 0, r.err
+		}
+		n := copy(data, r.data)
+		d.mu.Lock()
+		d.unread = append(d.unread, r.data[n:]...)
+		d.mu.Unlock()
+		return n, nil
+	case <-expired:
+		return 0, os.ErrDeadlineExceeded
+	}
+}
+
+func (d *DeviceTransport) readLoop() {
+	defer close(d.incoming)
+	buf := make([]byte, 64)
+	for {
+		n, err := d.f.Read(buf)
+		if n > 0 {
+			d.incoming <- deviceRead{data: append([]byte(nil), buf[:n]...)}
+		}
+		if err != nil {
+			d.mu.Lock()
+			d.readErr = err
+			d.mu.Unlock()
+			d.incoming <- deviceRead{err: err}
+			return
+		}
+	}
+}
+
+func (d *DeviceTransport) SetReadDeadline(t time.Time) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.readDeadline = t
+	return nil
+}
+
+func (d *DeviceTransport) Close() error {
+	return d.f.Close()
+}
+
+// DeviceID returns the IEEE 1284 device ID reported by the printer,
+// such as "MFG:EPSON;CMD:ESC/POS;MDL:TM-T20;". Only usblp devices on
+// linux support it.
+func (d *DeviceTransport) DeviceID() (string, error) {
+	return deviceID(d.f)
+}
+
+// ParseDeviceID splits an IEEE 1284 device ID into its keys. The long
+// key names (MANUFACTURER, MODEL, COMMAND SET) are folded into their
+// short forms MFG, MDL and CMD.
+func ParseDeviceID(id string) map[string]string {
+	aliases := map[string]string{
+		"MANUFACTURER": "MFG",
+		"MODEL":        "MDL",
+		"COMMAND SET":  "CMD",
+	}
+	fields := make(map[string]string)
+	for _, field := range strings.Split(id, ";") {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		key := strings.ToUpper(strings.TrimSpace(kv[0]))
+		if alias, ok := aliases[key]; ok {
+			key = alias
+		}
+		fields[key] = strings.TrimSpace(kv[1])
+	}
+	return fields
+}
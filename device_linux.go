@@ -0,0 +1,33 @@
+package thermalprinter
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+const deviceIDMax = 1024
+
+// LPIOC_GET_DEVICE_ID(len) from linux/usb/usblp.
+func lpiocGetDeviceID(size int) uintptr {
+	const iocRead = 2
+	return uintptr(iocRead)<<30 | uintptr(size)<<16 | 'P'<<8 | 1
+}
+
+func deviceID(f *os.File) (string, error) {
+	buf := make([]byte, deviceIDMax)
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), lpiocGetDeviceID(len(buf)), uintptr(unsafe.Pointer(&buf[0])))
+	if errno != 0 {
+		return "", errno
+	}
+	// The ID is prefixed by its big-endian length, which includes
+	// the two length bytes.
+	n := int(buf[0])<<8 | int(buf[1])
+	if n < 2 {
+		return "", nil
+	}
+	if n > len(buf) {
+		n = len(buf)
+	}
+	return string(buf[2:n]), nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package thermalprinter
+
+import (
+	"errors"
+	"os"
+)
+
+func deviceID(f *os.File) (string, error) {
+	return "", errors.New("thermalprinter: device ID is only supported on linux")
+}
@@ -0,0 +1,97 @@
+package thermalprinter
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestDeviceWrite(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lp0")
+	if err := os.WriteFile(path, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	d, err := OpenDevice(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := d.Write([]byte("hello\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := os.ReadFile(path); err != nil || string(got) != "hello\n" {
+		t.Errorf("device file = %q, %v, want %q", got, err, "hello\n")
+	}
+}
+
+func TestDeviceReadDeadline(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer w.Close()
+	d := &DeviceTransport{f: r, path: "pipe"}
+	defer d.Close()
+
+	d.SetReadDeadline(time.Now().Add(20 * time.Millisecond))
+	buf := make([]byte, 4)
+	if n, err := d.Read(buf); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Fatalf("Read with nothing sent = %d, %v, want os.ErrDeadlineExceeded", n, err)
+	}
+
+	// Replies longer than the buffer are kept for the next Read.
+	if _, err := w.Write([]byte{0x10, 0x14, 0x00, 0x00, 0x20}); err != nil {
+		t.Fatal(err)
+	}
+	d.SetReadDeadline(time.Now().Add(time.Second))
+	var got []byte
+	for len(got) < 5 {
+		n, err := d.Read(buf)
+		if err != nil {
+			t.Fatalf("Read after %x: %v", got, err)
+		}
+		got = append(got, buf[:n]...)
+	}
+	if string(got) != "\x10\x14\x00\x00\x20" {
+		t.Errorf("Read = % x, want 10 14 00 00 20", got)
+	}
+
+	w.Close()
+	if _, err := d.Read(buf); err == nil {
+		t.Error("Read after the writer closed succeeded")
+	}
+}
+
+func TestParseDeviceID(t *testing.T) {
+	tests := []struct {
+		id   string
+		want map[string]string
+	}{
+		{
+			"MFG:EPSON;CMD:ESC/POS;MDL:TM-T20;",
+			map[string]string{"MFG": "EPSON", "CMD": "ESC/POS", "MDL": "TM-T20"},
+		},
+		{
+			"MANUFACTURER:Adafruit; COMMAND SET:ESC/POS,PNG ;model:CSN-A2;CLS:PRINTER",
+			map[string]string{"MFG": "Adafruit", "CMD": "ESC/POS,PNG", "MDL": "CSN-A2", "CLS": "PRINTER"},
+		},
+		{"", map[string]string{}},
+		{"garbage;DES:Thermal:58mm", map[string]string{"DES": "Thermal:58mm"}},
+	}
+	for _, tt := range tests {
+		got := ParseDeviceID(tt.id)
+		if len(got) != len(tt.want) {
+			t.Errorf("ParseDeviceID(%q) = %v, want %v", tt.id, got, tt.want)
+			continue
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("ParseDeviceID(%q)[%q] = %q, want %q", tt.id, k, got[k], v)
+			}
+		}
+	}
+}
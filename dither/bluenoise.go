@@ -0,0 +1,263 @@
+// Code generated by "go test -run TestBlueNoiseTable -update"; DO NOT EDIT.
+
+package dither
+
+// blueNoiseRank is the 64x64 matrix of BlueNoise, row by row.
+var blueNoiseRank = []int{
+	3944, 1633, 3258, 3646, 2787, 616, 3305, 2091, 863, 2410, 1850, 3811, 2644, 1555, 1095, 229,
+	3906, 577, 3562, 304, 1276, 3315, 2023, 3745, 1560, 584, 3483, 1673, 3206, 3997, 1513, 2550,
+	3271, 1713, 2441, 672, 3137, 2145, 2765, 1887, 3108, 255, 2100, 2932, 21, 832, 2094, 536,
+	2596, 3897, 3418, 1179, 3231, 2408, 2990, 3948, 995, 2897, 2207, 579, 1012, 3653, 3187, 1227,
+	161, 2465, 1145, 2116, 1430, 2389, 169, 3580, 2849, 425, 2996, 1327, 18, 2360, 3421, 2861,
+	2270, 1406, 2717, 2338, 1798, 2918, 333, 965, 3234, 2335, 1348, 2835, 277, 2312, 1217, 1962,
+	3883, 451, 3601, 2002, 1562, 3911, 53, 783, 3715, 2521, 615, 3812, 1183, 3297, 3981, 1639,
+	3011, 788, 1509, 2516, 574, 1672, 195, 1473, 2008, 3717, 1261, 3432, 2643, 1573, 694, 1877,
+	3490, 2988, 439, 3881, 834, 3198, 1881, 1268, 1635, 4000, 750, 3289, 2034, 3706, 706, 1880,
+	894, 3244, 52, 3813, 741, 1486, 4020, 2670, 1918, 55, 3915, 921, 3565, 677, 3100, 15,
+	971, 2836, 1297, 225, 2950, 1170, 3328, 2293, 1494, 1020, 3492, 1738, 2683, 1500, 2399, 270,
+	3588, 2268, 73, 2923, 4082, 890, 3520, 2696, 711, 3259, 358, 1907, 6, 2296, 3777, 2739,
+	1467, 722, 1788, 2591, 316, 2889, 3776, 551, 2669, 2173, 1127, 2536, 349, 1432, 3054, 398,
+	4092, 1658, 2131, 1092, 3125, 2454, 500, 1163, 3578, 736, 2576, 2056, 1724, 2693, 3775, 2244,
+	3212, 1611, 2333, 3396, 603, 2570, 1733, 465, 4030, 2974, 167, 2058, 752, 410, 3180, 952,
+	1292, 1934, 3732, 1087, 1805, 2178, 3134, 433, 2393, 1640, 2754, 4006, 1385, 3288, 450, 964,
+	4090, 2337, 3325, 3599, 1602, 1190, 2314, 983, 3236, 99, 3558, 1752, 3902, 1009, 2200, 2606,
+	1251, 3550, 2909, 444, 3635, 1848, 3398, 2242, 1652, 3061, 1274, 3355, 173, 1446, 1052, 1822,
+	640, 4073, 856, 3751, 2133, 950, 3600, 2722, 1945, 1266, 2431, 3219, 3936, 2886, 1890, 3497,
+	2654, 553, 3262, 2749, 310, 3666, 1287, 1867, 3918, 1140, 816, 2156, 2944, 1105, 2560, 2108,
+	3050, 1220, 57, 941, 2090, 4026, 183, 3522, 1976, 1514, 3038, 546, 2744, 3381, 1574, 3194,
+	185, 680, 1964, 2623, 1371, 102, 876, 2757, 237, 3845, 468, 2288, 4028, 3021, 390, 3388,
+	2597, 322, 2768, 1779, 89, 3210, 1407, 211, 840, 3423, 573, 1578, 1005, 2281, 1390, 212,
+	4015, 1690, 2226, 802, 1529, 2476, 647, 2937, 112, 3195, 3603, 317, 666, 3808, 1723, 271,
+	608, 1928, 2760, 3148, 619, 2663, 1697, 2943, 771, 3764, 2405, 901, 2077, 117, 763, 3855,
+	2374, 1511, 3293, 906, 3992, 2159, 3013, 3662, 1448, 1973, 2823, 1103, 629, 1916, 2328, 3699,
+	1237, 2083, 1449, 3020, 1159, 3977, 2311, 2910, 3806, 2155, 2793, 3546, 76, 3750, 684, 2500,
+	2983, 1167, 118, 3841, 2893, 3361, 1029, 3786, 2049, 1444, 2456, 1782, 3451, 1488, 2817, 3379,
+	3648, 1563, 3863, 2294, 1377, 3359, 482, 2455, 1414, 330, 1198, 4010, 1687, 3624, 2854, 1903,
+	1064, 2751, 3748, 253, 2477, 1546, 576, 1085, 2446, 776, 3281, 1608, 3519, 2738, 823, 1643,
+	127, 3253, 3657, 487, 2540, 1911, 642, 1627, 1131, 350, 1806, 1301, 2613, 1746, 3316, 2074,
+	845, 3406, 2426, 1316, 1981, 268, 1678, 2313, 543, 2796, 910, 3035, 2252, 80, 883, 2053,
+	2513, 179, 1088, 393, 3721, 2012, 1117, 3940, 3438, 2134, 2627, 2964, 453, 2317, 1321, 295,
+	3440, 522, 1726, 1235, 3207, 3488, 2011, 3931, 3149, 336, 3720, 2484, 41, 1323, 3128, 3973,
+	2820, 700, 2297, 936, 3126, 335, 3746, 3341, 2529, 3188, 4064, 524, 3109, 1104, 436, 3851,
+	1594, 378, 3617, 3056, 595, 4038, 2727, 3448, 1255, 3719, 238, 4047, 1202, 2635, 3905, 1315,
+	775, 3237, 1829, 3046, 847, 2855, 217, 1898, 683, 3079, 58, 1505, 3387, 1004, 3165, 2567,
+	4029, 2054, 3073, 2271, 784, 420, 2721, 146, 1744, 1365, 2125, 924, 3850, 2066, 434, 2439,
+	1111, 1953, 3872, 1718, 3575, 1289, 2172, 954, 9, 1980, 822, 2390, 3713, 2007, 2966, 1272,
+	2771, 2167, 1773, 999, 2557, 1490, 830, 23, 1941, 3254, 1636, 626, 1891, 3296, 422, 2929,
+	3553, 2208, 3965, 2593, 1579, 3573, 2510, 3246, 1613, 1019, 3752, 1979, 633, 3791, 1809, 714,
+	1477, 956, 3, 2800, 3963, 1833, 1295, 3583, 2365, 2813, 547, 2978, 1699, 3250, 745, 1512,
+	3504, 278, 1392, 2876, 113, 2671, 1590, 2993, 3626, 1374, 2870, 1598, 197, 892, 2448, 109,
+	3365, 716, 3873, 151, 3295, 2209, 3691, 3041, 2404, 987, 2612, 2194, 3643, 1050, 2336, 1728,
+	1378, 280, 1213, 678, 39, 2187, 1293, 498, 2711, 3469, 2415, 1267, 2728, 2166, 140, 2941,
+	2351, 3266, 3614, 1572, 1040, 2577, 3043, 610, 959, 3269, 4083, 1262, 257, 2611, 3698, 2214,
+	3093, 2573, 622, 3348, 2319, 735, 4005, 464, 2460, 624, 3910, 2250, 3164, 3517, 1786, 4040,
+	1506, 2327, 1174, 2917, 1919, 429, 1129, 1765, 528, 3888, 374, 2969, 1504, 157, 3132, 580,
+	3809, 2656, 2991, 2072, 3270, 4041, 898, 3682, 1855, 205, 765, 4076, 328, 3350, 1637, 3895,
+	1207, 343, 2127, 649, 3728, 172, 1968, 3896, 1621, 87, 1826, 2291, 3552, 1107, 1885, 97,
+	934, 4056, 2121, 1086, 1698, 3506, 1149, 2098, 1656, 3329, 1066, 346, 1338, 2658, 566, 1049,
+	3131, 287, 2630, 3586, 1386, 3946, 2799, 3402, 1470, 2736, 1279, 3439, 800, 3960, 2709, 1984,
+	865, 1599, 3705, 992, 1760, 437, 2354, 2908, 1423, 2245, 2856, 1591, 3075, 977, 2525, 572,
+	3467, 2707, 1785, 3130, 2425, 1431, 3225, 2259, 1191, 2600, 3426, 796, 2779, 520, 3835, 1439,
+	2812, 1778, 409, 3763, 3019, 258, 2732, 3155, 158, 2652, 1913, 3793, 744, 2135, 3667, 2840,
+	2016, 3480, 1704, 541, 871, 2489, 291, 782, 2081, 3233, 71, 2044, 2481, 1661, 1133, 3309,
+	476, 2301, 156, 3446, 2774, 1474, 3141, 285, 3919, 1096, 3605, 1915, 542, 1417, 3673, 1954,
+	881, 1401, 4059, 1083, 456, 3472, 853, 275, 2907, 3792, 396, 2064, 1559, 3037, 2449, 3255,
+	652, 3577, 1346, 2624, 888, 2015, 1389, 3779, 896, 3574, 1465, 2879, 3240, 1696, 33, 1443,
+	463, 947, 3916, 2235, 3242, 1849, 3534, 2350, 4086, 1015, 1719, 3818, 540, 3532, 222, 2440,
+	1859, 3072, 1334, 2475, 731, 3816, 1146, 2062, 661, 3211, 25, 2609, 3390, 2186, 2896, 82,
+	2419, 2995, 248, 2157, 2866, 1702, 2542, 3593, 1771, 695, 1411, 3147, 3953, 955, 324, 1994,
+	1155, 2353, 3136, 337, 2276, 3980, 667, 1816, 2247, 491, 2412, 208, 1010, 2503, 3970, 2224,
+	3042, 2581, 1291, 84, 2899, 1564, 1080, 168, 2931, 650, 2580, 2985, 1332, 2128, 2903, 4042,
+	3349, 597, 3882, 2025, 314, 1810, 2589, 3470, 1657, 2432, 887, 1280, 3969, 370, 1172, 3877,
+	1586, 3409, 742, 3631, 1252, 3858, 548, 1062, 2182, 3321, 2498, 1125, 24, 2239, 1625, 3434,
+	3985, 78, 1847, 3516, 1558, 3263, 108, 2994, 3485, 1259, 4075, 1998, 3428, 549, 1206, 3306,
+	739, 3692, 1892, 3399, 696, 2595, 3773, 1426, 1942, 3669, 1173, 315, 3226, 737, 1491, 1046,
+	1619, 2241, 944, 2869, 3604, 3241, 915, 193, 2798, 3690, 2144, 3097, 1793, 757, 2701, 3154,
+	493, 2266, 1797, 2655, 35, 1960, 3183, 1524, 3989, 206, 1933, 3473, 2699, 3660, 2984, 517,
+	2647, 966, 2863, 719, 1122, 2804, 2402, 962, 1620, 2713, 774, 2975, 1416, 3632, 2745, 1753,
+	320, 1517, 2347, 1017, 4019, 389, 2197, 3320, 483, 2418, 3378, 1811, 2321, 3683, 2690, 178,
+	2519, 3527, 14, 1257, 1569, 554, 2307, 4048, 1355, 497, 1593, 233, 2549, 3524, 1497, 1992,
+	998, 3804, 1307, 3318, 918, 2308, 2814, 385, 2574, 3002, 926, 592, 1438, 1861, 824, 1311,
+	2179, 1507, 3778, 2086, 3616, 406, 1906, 3885, 417, 3363, 269, 1708, 2265, 137, 880, 2078,
+	3887, 2845, 192, 3146, 2003, 1270, 3003, 905, 2790, 1508, 115, 4002, 980, 394, 1922, 3833,
+	1181, 3138, 1869, 3949, 2659, 3077, 1134, 1873, 2954, 829, 3861, 3343, 1099, 2254, 134, 3650,
+	2518, 326, 2898, 591, 4011, 1397, 3563, 756, 1258, 1681, 3613, 2282, 4035, 226, 2464, 3810,
+	3351, 186, 3157, 539, 2507, 1396, 3303, 1188, 2146, 2486, 3780, 1098, 3167, 3959, 2493, 3026,
+	1138, 662, 3515, 2677, 1645, 3709, 8, 1754, 3874, 709, 2140, 2824, 1654, 3458, 3065, 797,
+	467, 2766, 689, 2396, 266, 2055, 3413, 90, 3564, 2196, 2720, 1974, 594, 4063, 2772, 835,
+	1376, 3203, 2107, 1692, 2509, 259, 1817, 3302, 3904, 2124, 123, 3218, 2753, 1169, 3081, 421,
+	1914, 2700, 1236, 1740, 3857, 19, 2705, 596, 3087, 859, 1923, 2689, 660, 1879, 382, 1576,
+	3609, 2211, 1395, 873, 462, 2413, 3360, 2638, 1109, 3569, 3169, 1281, 625, 2452, 1399, 2132,
+	4077, 1685, 3362, 1033, 1480, 3676, 806, 1631, 2497, 392, 997, 1421, 3078, 1684, 458, 3389,
+	1841, 3939, 91, 1166, 3736, 3057, 976, 2272, 341, 2862, 1081, 1571, 537, 2006, 3596, 1556,
+	725, 4088, 2392, 937, 3016, 2070, 3455, 1770, 4033, 1482, 68, 3678, 1356, 3474, 1044, 3245,
+	49, 2533, 1799, 3964, 2948, 1200, 658, 2092, 216, 1888, 2511, 300, 3921, 2962, 75, 3570,
+	1271, 223, 2163, 3832, 2895, 403, 2616, 3199, 1240, 3749, 3290, 51, 3634, 2148, 1197, 2960,
+	2378, 655, 2666, 3441, 726, 1975, 2747, 1476, 651, 3726, 1920, 2592, 3422, 879, 2833, 2289,
+	1093, 3103, 245, 3531, 635, 1532, 994, 2428, 381, 2838, 2082, 3114, 477, 2298, 2795, 2033,
+	3831, 578, 3200, 281, 2183, 3463, 1609, 4066, 3085, 1435, 927, 3333, 2043, 1058, 1764, 2387,
+	3220, 882, 2552, 552, 1865, 1110, 4025, 2122, 634, 1921, 1577, 2883, 2485, 911, 3899, 180,
+	1580, 1039, 3032, 2169, 1381, 154, 3639, 3127, 2480, 3261, 773, 3846, 246, 1309, 3922, 54,
+	3493, 1839, 1464, 2191, 2675, 3961, 170, 3638, 1263, 3380, 988, 2538, 1665, 3975, 734, 1471,
+	951, 2698, 1246, 3733, 1900, 957, 2703, 373, 2260, 3802, 496, 2725, 1472, 3436, 668, 2851,
+	1971, 3722, 1325, 3537, 3119, 2302, 1542, 131, 3012, 3929, 2300, 768, 428, 1814, 2629, 3518,
+	2040, 3718, 413, 1748, 4080, 2386, 515, 1208, 1737, 10, 1429, 2367, 3024, 2114, 1682, 2512,
+	593, 2773, 3772, 447, 1284, 2951, 1674, 3104, 2203, 679, 3860, 236, 1215, 3222, 164, 3529,
+	3009, 1707, 2316, 789, 2844, 202, 3661, 1290, 728, 2878, 1677, 3649, 184, 2176, 3839, 388,
+	1565, 92, 2764, 1700, 293, 766, 3331, 2687, 1022, 331, 1195, 3478, 3825, 1409, 3248, 582,
+	1324, 2450, 3179, 815, 2826, 3340, 917, 3926, 2184, 3512, 1018, 1835, 490, 3656, 779, 3267,
+	1340, 2085, 916, 3372, 2372, 820, 1987, 531, 2665, 1568, 1917, 3508, 2150, 2706, 1807, 2436,
+	435, 4032, 107, 3539, 1459, 3307, 1774, 2515, 3172, 1939, 1135, 2364, 781, 3105, 985, 2586,
+	3999, 2223, 3311, 1013, 2434, 3878, 1310, 3693, 1767, 2556, 3068, 2031, 2740, 214, 2264, 870,
+	2819, 30, 3824, 1199, 227, 1647, 2029, 2891, 308, 2594, 3098, 4060, 2679, 1132, 2865, 298,
+	4001, 3014, 116, 1756, 3819, 263, 3427, 3901, 1068, 3186, 418, 2864, 914, 519, 3730, 1154,
+	2093, 1373, 3150, 1983, 570, 2373, 1061, 3783, 448, 3484, 284, 4037, 2680, 1730, 3581, 1201,
+	2986, 826, 471, 3641, 2009, 2877, 231, 2105, 701, 3405, 1496, 516, 939, 3143, 1667, 4024,
+	3486, 1858, 1503, 2257, 2639, 3700, 1361, 628, 3754, 1584, 733, 354, 1526, 3530, 1950, 2340,
+	1659, 1114, 2554, 3217, 1192, 2602, 1469, 2299, 111, 3672, 2398, 1419, 4091, 1610, 3051, 799,
+	3425, 2767, 975, 2599, 3937, 3027, 31, 2175, 1501, 899, 2075, 2952, 1288, 44, 2099, 521,
+	1775, 2603, 1481, 3074, 1229, 620, 1644, 3129, 2355, 1, 4089, 1860, 3621, 2508, 1248, 357,
+	2109, 1007, 3332, 664, 3010, 355, 3190, 2445, 1168, 1959, 3393, 2171, 2478, 105, 929, 3356,
+	460, 3734, 730, 2174, 494, 3066, 933, 2894, 1843, 1241, 693, 3431, 0, 2248, 2579, 254,
+	3864, 612, 1791, 282, 1222, 1675, 764, 2887, 3955, 2541, 3282, 1587, 638, 3214, 2395, 3414,
+	215, 3828, 1937, 119, 4043, 2712, 3494, 1071, 3797, 1369, 2761, 1113, 2177, 110, 3408, 2662,
+	3055, 455, 2488, 3993, 1893, 1042, 2141, 3449, 124, 2956, 978, 3821, 1242, 3063, 3938, 1393,
+	2631, 2010, 3505, 1336, 4068, 1897, 3511, 311, 3974, 2152, 3095, 2684, 1886, 1210, 3275, 1989,
+	1487, 2391, 3352, 3714, 2278, 3213, 3587, 1832, 544, 1211, 209, 3633, 1925, 3889, 902, 1358,
+	2848, 1051, 3339, 2424, 886, 1878, 361, 2569, 790, 2004, 441, 3181, 724, 3927, 1801, 632,
+	1440, 3560, 1688, 93, 1331, 3767, 787, 1664, 4022, 2621, 290, 1694, 2786, 485, 1821, 767,
+	2959, 207, 1630, 2831, 74, 2273, 758, 1703, 2619, 461, 1552, 979, 3900, 630, 3585, 963,
+	2802, 65, 1313, 2853, 889, 369, 1370, 2342, 3450, 2816, 2204, 833, 2710, 318, 2548, 3671,
+	681, 2231, 395, 2919, 1363, 3654, 2210, 1515, 3364, 2852, 3665, 2444, 1585, 2885, 1063, 2379,
+	3856, 836, 2752, 2236, 3227, 2561, 2859, 506, 1394, 2222, 3358, 656, 3679, 2137, 3276, 2383,
+	3830, 1056, 3205, 671, 2531, 3273, 1427, 3680, 1108, 3229, 3627, 188, 2331, 2913, 372, 1758,
+	3176, 3979, 2161, 589, 1912, 4067, 2634, 143, 1008, 1715, 3842, 1468, 3091, 1120, 1662, 2047,
+	3525, 1547, 3909, 1745, 604, 3204, 85, 3990, 587, 1727, 144, 1204, 3769, 273, 3278, 2052,
+	162, 2987, 1116, 3630, 691, 256, 1824, 3551, 3060, 849, 1905, 2530, 1380, 1011, 48, 1592,
+	401, 2118, 3684, 1862, 1214, 3848, 365, 2915, 2417, 718, 2005, 2769, 1691, 1353, 3803, 2479,
+	749, 1123, 1683, 3475, 3053, 1112, 2061, 3739, 3034, 492, 3301, 104, 2330, 4084, 512, 3161,
+	20, 2632, 1147, 3477, 2089, 2605, 1232, 2967, 2376, 973, 3264, 1944, 2255, 864, 2685, 1554,
+	3429, 1899, 438, 1597, 2065, 3870, 1030, 2290, 64, 1226, 3943, 351, 3417, 2922, 4007, 2668,
+	3435, 1387, 2453, 424, 3015, 798, 2138, 1830, 46, 3794, 1216, 3403, 828, 3142, 2106, 148,
+	3541, 2295, 397, 2469, 175, 1550, 2784, 740, 1398, 2523, 2067, 785, 3501, 1956, 2788, 1308,
+	2394, 772, 3116, 187, 968, 3765, 411, 1961, 1402, 3840, 2559, 530, 3482, 1388, 4008, 550,
+	1238, 2546, 3972, 3083, 1299, 2471, 3243, 1553, 3651, 2646, 3152, 1614, 2258, 786, 1955, 1141,
+	609, 2900, 913, 4027, 1595, 2692, 3592, 961, 3135, 1518, 2352, 264, 4051, 529, 1152, 2686,
+	1498, 2957, 3664, 907, 3903, 3322, 449, 3487, 1874, 3994, 1082, 2912, 1413, 297, 877, 3727,
+	2068, 4021, 1802, 2810, 2382, 1623, 3099, 818, 3496, 262, 2890, 1604, 3069, 342, 2420, 2961,
+	3559, 891, 2181, 12, 3481, 827, 377, 2811, 699, 1952, 235, 1055, 3784, 419, 2520, 3549,
+	1720, 3252, 218, 2249, 3444, 141, 1337, 2517, 3968, 614, 3029, 1784, 2571, 1988, 3737, 3373,
+	569, 1957, 1296, 2637, 1812, 2198, 1054, 2422, 27, 3133, 387, 3677, 1751, 2430, 3308, 1622,
+	260, 3367, 545, 1260, 3540, 676, 3957, 2718, 2195, 1768, 743, 1106, 3760, 1857, 825, 2103,
+	196, 1693, 2867, 631, 2641, 1710, 4095, 2149, 1350, 3844, 2472, 2881, 1852, 3191, 1408, 152,
+	3817, 2046, 2650, 1097, 1895, 648, 3235, 1749, 243, 2130, 3528, 1016, 1366, 2906, 230, 1705,
+	996, 4014, 77, 3113, 527, 1368, 2916, 3871, 1638, 1283, 2123, 2583, 599, 3908, 2949, 1100,
+	2704, 1457, 2304, 3047, 296, 2039, 1382, 32, 1176, 3163, 4062, 2303, 100, 2770, 3645, 1184,
+	3232, 3847, 1375, 3723, 1985, 1175, 2953, 139, 3445, 938, 525, 1456, 3579, 723, 2791, 2229,
+	940, 533, 1485, 3892, 3089, 2369, 3747, 1121, 2871, 854, 2649, 368, 3644, 686, 2238, 3080,
+	2487, 3313, 2111, 803, 3815, 3268, 345, 869, 3547, 2846, 808, 3347, 1223, 86, 2151, 687,
+	1931, 3610, 949, 3867, 1701, 2640, 3265, 2326, 3687, 571, 1995, 2653, 1341, 3291, 1618, 657,
+	2323, 430, 2462, 969, 3330, 469, 3668, 1875, 2371, 3025, 3324, 2165, 22, 1224, 4069, 1629,
+	3101, 3523, 2806, 325, 817, 1454, 415, 2021, 3385, 1589, 3869, 1909, 3189, 1528, 3933, 843,
+	1329, 412, 2781, 1516, 2414, 1757, 2672, 1978, 2285, 219, 3708, 1815, 2783, 1567, 3238, 3795,
+	452, 2935, 66, 2170, 738, 3629, 474, 972, 2777, 1436, 234, 3521, 900, 459, 2042, 4036,
+	2809, 1819, 3156, 166, 2221, 2741, 846, 1545, 423, 1164, 1739, 3879, 2582, 1943, 3383, 301,
+	2359, 1250, 1772, 2193, 3695, 2945, 2590, 4074, 585, 2329, 129, 1180, 2385, 2807, 38, 2027,
+	3756, 1820, 3476, 1070, 3623, 191, 1171, 3401, 663, 1420, 2401, 399, 4053, 855, 2381, 1254,
+	1655, 2514, 3437, 1428, 2843, 1225, 1929, 3971, 1676, 3395, 2971, 1780, 3913, 2564, 3120, 47,
+	1352, 778, 3594, 1596, 3983, 1314, 3175, 2504, 3962, 2759, 252, 851, 2976, 557, 1060, 2734,
+	688, 3942, 174, 3272, 1126, 1924, 5, 1023, 3033, 1359, 2758, 3459, 532, 1003, 3338, 2543,
+	637, 2999, 147, 2256, 705, 3084, 3930, 1605, 2927, 3843, 3160, 1124, 2060, 3526, 221, 3122,
+	717, 1084, 1870, 4078, 276, 3168, 2539, 142, 753, 2411, 1143, 673, 2341, 1521, 1059, 3495,
+	2263, 2968, 1118, 2610, 568, 1936, 61, 3507, 674, 1949, 3724, 1603, 3542, 2188, 1447, 3743,
+	2022, 2933, 895, 2625, 588, 3544, 1540, 3299, 1837, 3801, 793, 2112, 3988, 1853, 1422, 3597,
+	1136, 1616, 4079, 2620, 1458, 2069, 526, 2474, 63, 904, 1689, 2661, 621, 2874, 1842, 3947,
+	2756, 3326, 518, 2357, 862, 1536, 3465, 2189, 3115, 3854, 363, 2059, 3711, 312, 2730, 1896,
+	561, 3710, 220, 2120, 3028, 3758, 1079, 2185, 1412, 3257, 2344, 1249, 356, 3151, 2491, 94,
+	3353, 1499, 2325, 3807, 1320, 2119, 2535, 480, 2253, 189, 2920, 1575, 313, 3110, 2274, 242,
+	2825, 2147, 473, 3386, 903, 3697, 2743, 1115, 1969, 3464, 2243, 272, 3344, 1450, 984, 2218,
+	359, 1343, 3636, 3040, 2041, 3729, 446, 1000, 1344, 1851, 2617, 3070, 1233, 3374, 837, 3898,
+	1484, 2483, 1736, 3345, 804, 1557, 2750, 445, 3004, 919, 122, 2847, 1882, 3986, 819, 1776,
+	1153, 442, 1854, 3102, 153, 2850, 3987, 897, 3628, 1285, 2458, 3738, 931, 2681, 601, 3826,
+	839, 3185, 1253, 1823, 2880, 380, 1731, 4031, 3017, 575, 3716, 1278, 3893, 2447, 2, 3744,
+	1967, 2588, 1706, 114, 1142, 2723, 1755, 2904, 3670, 565, 3430, 182, 1670, 2928, 2233, 136,
+	3139, 920, 4016, 1333, 339, 3489, 2315, 4058, 1734, 3612, 2572, 3407, 1028, 495, 2748, 3576,
+	2965, 4050, 748, 3598, 1651, 400, 1212, 3067, 1742, 3415, 627, 1986, 3280, 1347, 1722, 3420,
+	1965, 2524, 3894, 17, 2377, 3536, 1360, 265, 2348, 1527, 2762, 1908, 690, 2981, 1581, 3192,
+	1165, 2942, 791, 3827, 2457, 617, 4003, 26, 2397, 1533, 861, 4071, 2490, 555, 1415, 3509,
+	1958, 470, 2792, 2269, 2946, 1846, 644, 1264, 309, 2050, 607, 1561, 3774, 2126, 1302, 2363,
+	279, 2036, 2607, 1074, 2286, 3368, 2017, 692, 2688, 250, 2998, 1187, 50, 4049, 2388, 1076,
+	177, 1493, 712, 3334, 1035, 2032, 3086, 814, 3377, 1021, 130, 3230, 1139, 2087, 3557, 586,
+	4018, 306, 2160, 3411, 1410, 3071, 2129, 1193, 3292, 2815, 2168, 1119, 1966, 3781, 1024, 2841,
+	2527, 1245, 3567, 37, 1025, 3770, 2492, 3354, 2875, 3859, 1162, 2279, 2925, 72, 3221, 1617,
+	675, 3443, 1433, 2892, 535, 3785, 2501, 1437, 3935, 2212, 1615, 3652, 2101, 2742, 427, 3076,
+	3768, 2775, 2113, 2934, 1641, 508, 3761, 2482, 1787, 3620, 2217, 3991, 391, 2716, 922, 2368,
+	3457, 1531, 3177, 1006, 190, 1650, 3460, 760, 1871, 261, 3589, 3001, 103, 3224, 1762, 299,
+	3952, 707, 1679, 2051, 3111, 1495, 210, 935, 1538, 2409, 232, 3346, 813, 1828, 3642, 991,
+	3829, 2443, 29, 3277, 878, 1729, 128, 3479, 466, 1069, 2563, 703, 3124, 866, 1523, 1935,
+	567, 1178, 3625, 303, 3996, 2667, 1150, 171, 2930, 583, 1286, 2568, 1732, 3762, 203, 1840,
+	2545, 682, 2737, 1999, 3934, 2384, 443, 2682, 3954, 1383, 618, 1624, 2695, 812, 3556, 2158,
+	1534, 3317, 2433, 3685, 754, 2615, 3925, 1948, 3140, 715, 2805, 1404, 4045, 2532, 511, 3030,
+	1769, 1148, 1927, 3907, 2162, 3158, 1144, 2858, 1940, 3202, 3838, 286, 1763, 3412, 3924, 2555,
+	3285, 2310, 1790, 867, 1379, 3239, 2246, 1588, 3923, 2018, 3153, 755, 1455, 2940, 3286, 1318,
+	59, 3834, 1209, 360, 2911, 868, 3584, 2037, 1031, 3145, 2237, 3865, 1205, 2370, 484, 3007,
+	970, 163, 2830, 479, 1349, 3304, 2230, 505, 3533, 1789, 3702, 2024, 371, 1196, 2115, 2697,
+	241, 3376, 2622, 384, 1354, 2673, 4081, 801, 2358, 1502, 942, 2872, 2416, 1151, 96, 1372,
+	807, 239, 3419, 2565, 2080, 643, 3572, 416, 974, 2729, 36, 3500, 2283, 514, 1026, 2096,
+	3121, 1663, 2318, 3618, 1792, 1303, 3058, 1583, 305, 2505, 3394, 376, 2030, 3742, 1342, 2551,
+	3820, 2026, 1102, 4085, 1884, 126, 1038, 2719, 1298, 4, 993, 2628, 3162, 1600, 3548, 838,
+	3982, 1510, 946, 3008, 670, 1628, 334, 1795, 3741, 67, 3323, 1993, 510, 3582, 2205, 3006,
+	3798, 2726, 1519, 3875, 70, 2955, 1741, 2598, 3357, 1434, 3805, 1094, 1932, 4055, 2678, 3561,
+	486, 2868, 912, 3294, 646, 2528, 62, 3822, 2801, 729, 1766, 923, 3090, 16, 3382, 1777,
+	659, 3184, 1466, 2262, 2905, 3607, 1606, 3048, 3984, 2473, 3312, 636, 3836, 132, 2992, 2356,
+	558, 2014, 3655, 2292, 3319, 3606, 2526, 3094, 645, 2645, 1182, 3876, 1535, 2674, 669, 1632,
+	1977, 1053, 504, 3107, 967, 1300, 4087, 759, 2332, 1868, 366, 2470, 3082, 240, 1479, 842,
+	3920, 2048, 1362, 224, 4093, 2199, 3369, 1156, 2139, 3640, 1403, 3932, 2708, 1566, 1057, 2857,
+	213, 2494, 3462, 267, 831, 2400, 613, 1996, 364, 2164, 1424, 1750, 2320, 1014, 1863, 1265,
+	3182, 2735, 88, 1219, 1872, 199, 1037, 2110, 1351, 3571, 2251, 302, 3052, 1027, 4065, 353,
+	3249, 3591, 2466, 2219, 3686, 1947, 2834, 155, 1231, 3701, 2808, 1666, 704, 3336, 1844, 2406,
+	145, 2562, 3201, 1910, 2746, 1520, 809, 1818, 538, 2938, 176, 1963, 2427, 697, 3694, 2216,
+	3978, 930, 1686, 3789, 1317, 3170, 3853, 1185, 3400, 848, 3735, 3023, 475, 2797, 3941, 340,
+	3707, 1671, 747, 4023, 2901, 1478, 3890, 3260, 431, 2939, 1804, 746, 3454, 1747, 2287, 2884,
+	1294, 200, 1721, 702, 1445, 347, 3502, 2095, 3256, 523, 928, 3880, 1304, 2633, 3611, 1160,
+	1541, 3757, 598, 1075, 3696, 426, 3096, 3945, 2467, 3456, 1189, 3287, 319, 2982, 1827, 362,
+	1247, 2001, 3018, 454, 2604, 1803, 288, 2691, 1680, 2829, 149, 1244, 3608, 2028, 1462, 2506,
+	909, 2136, 3371, 2380, 509, 2020, 794, 2463, 1634, 953, 3998, 2575, 1269, 60, 3703, 858,
+	2117, 2657, 3891, 2989, 3337, 2585, 908, 1582, 2437, 2979, 1990, 3433, 79, 2073, 564, 3022,
+	884, 3392, 1761, 2970, 2403, 1335, 2035, 138, 1001, 1646, 2280, 810, 4070, 1322, 2362, 3503,
+	3228, 2755, 708, 3410, 2104, 982, 3555, 2309, 560, 4034, 1904, 2496, 872, 3274, 42, 3499,
+	2924, 432, 1453, 1043, 3174, 2648, 3513, 13, 3731, 2213, 249, 3284, 2038, 2980, 2534, 534,
+	3366, 1543, 1034, 7, 2154, 1243, 3966, 639, 3622, 251, 1451, 2361, 2902, 1048, 3950, 2261,
+	2733, 407, 2206, 11, 841, 3404, 2558, 3595, 2827, 472, 3759, 2664, 1709, 3118, 499, 875,
+	1549, 45, 2345, 1442, 4004, 159, 3059, 1483, 1072, 3300, 386, 3123, 1626, 590, 2234, 1137,
+	1781, 3849, 2785, 3637, 323, 1305, 1743, 3049, 1194, 2832, 1425, 600, 3823, 943, 1460, 1926,
+	3976, 383, 2839, 3566, 1831, 457, 3092, 2731, 1808, 1089, 4044, 720, 1711, 3251, 321, 1813,
+	1256, 4017, 1461, 3215, 3862, 1716, 344, 1203, 1901, 3178, 1367, 56, 2071, 1078, 3602, 2547,
+	3912, 1876, 3681, 1067, 2724, 1725, 795, 3790, 2102, 2626, 1384, 2275, 3674, 2714, 4094, 3088,
+	713, 2421, 95, 1864, 2220, 3956, 623, 2349, 414, 1938, 3144, 2459, 1669, 198, 3658, 3036,
+	1158, 2305, 1695, 665, 2495, 3688, 1492, 106, 2201, 3196, 2537, 408, 3771, 1319, 2423, 3510,
+	751, 2914, 2499, 986, 2057, 602, 3106, 4009, 777, 2180, 3538, 732, 3866, 2776, 201, 2143,
+	1177, 2873, 307, 3247, 559, 2438, 2888, 3375, 28, 727, 3884, 981, 181, 1312, 1866, 294,
+	1537, 3397, 1277, 770, 3327, 2803, 1002, 3796, 3384, 780, 3967, 1077, 3424, 2794, 2346, 327,
+	805, 3216, 3787, 1218, 2963, 960, 2322, 3782, 1326, 821, 3514, 1902, 2715, 811, 3062, 125,
+	3740, 1834, 507, 3554, 2837, 1328, 2660, 2343, 1612, 244, 2921, 2468, 1475, 3208, 1783, 581,
+	3370, 792, 2267, 1345, 3590, 2000, 348, 1186, 1668, 2789, 1951, 3452, 2926, 2142, 860, 3619,
+	2676, 2088, 3995, 2544, 1601, 160, 2079, 1441, 2553, 1717, 83, 2232, 503, 1972, 1239, 3535,
+	1856, 2578, 165, 2076, 3442, 289, 1889, 3283, 489, 2997, 1539, 43, 2225, 3663, 1525, 2097,
+	3197, 1073, 2324, 274, 1648, 3788, 101, 1047, 3725, 3310, 1157, 1930, 283, 925, 3800, 2651,
+	1530, 4052, 1845, 2958, 885, 1551, 3958, 2227, 3615, 3166, 404, 1570, 611, 3917, 2442, 3173,
+	563, 1032, 379, 3044, 1128, 3647, 3159, 292, 2973, 1234, 3568, 3031, 1522, 3868, 698, 2936,
+	1544, 4046, 556, 1400, 2642, 710, 4013, 1041, 2782, 2013, 3951, 654, 2860, 1045, 481, 2618,
+	194, 1405, 3914, 3298, 769, 2192, 3447, 1883, 685, 2694, 501, 4039, 3416, 2334, 1306, 3039,
+	81, 2429, 478, 3712, 121, 2636, 3064, 513, 857, 2366, 1273, 2566, 3314, 1130, 69, 1391,
+	1759, 2882, 3689, 1946, 606, 2284, 1735, 893, 3766, 1982, 352, 2587, 958, 3209, 2461, 98,
+	2215, 844, 3117, 3659, 1642, 3045, 2451, 1452, 332, 2306, 1228, 3466, 1796, 3279, 4061, 1712,
+	3543, 2947, 1997, 2601, 1275, 2977, 402, 2522, 3112, 1364, 2228, 1653, 2822, 375, 2084, 761,
+	3498, 1221, 3193, 1649, 2153, 1101, 3491, 1418, 1894, 4057, 135, 3704, 1800, 2763, 2063, 3799,
+	3453, 2240, 120, 1489, 3342, 2608, 4012, 502, 2375, 2828, 721, 4072, 1838, 405, 1463, 3755,
+	3335, 1282, 2780, 2277, 932, 34, 3461, 1836, 3675, 3223, 850, 2502, 228, 1339, 2407, 852,
+	2202, 562, 948, 40, 1794, 4054, 1090, 1548, 3852, 150, 3468, 990, 641, 3171, 3753, 1714,
+	2614, 1991, 945, 2821, 3928, 653, 2435, 204, 3000, 2702, 1036, 2190, 488, 874, 3005, 338,
+	762, 1161, 2778, 3837, 989, 329, 1357, 3471, 1091, 1607, 3391, 1330, 2339, 3545, 2842, 1065,
+	1970, 247, 1825, 440, 3814, 2045, 1230, 605, 2584, 133, 1660, 3886, 2972, 2019, 367, 2818,
+}
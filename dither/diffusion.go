@@ -0,0 +1,124 @@
+package dither
+
+import "image"
+
+// Tap is one entry of an error diffusion kernel: the share of a
+// pixel's quantisation error passed to the pixel DX columns ahead and
+// DY rows below.
+type Tap struct {
+	DX, DY int
+	Weight float64
+}
+
+// ErrorDiffusion thresholds pixels one at a time and pushes the
+// difference onto neighbours that have not been visited yet. With
+// Serpentine set, odd rows are scanned right to left and the kernel is
+// mirrored, which breaks up the diagonal worms of plain raster order.
+type ErrorDiffusion struct {
+	Kernel     []Tap
+	Divisor    float64
+	Serpentine bool
+}
+
+// The kernels carry their usual divisors; Atkinson deliberately
+// diffuses only 6/8 of the error, which keeps highlights and shadows
+// clean at the cost of some tonal range.
+var (
+	FloydSteinberg = ErrorDiffusion{
+		Kernel: []Tap{
+			{1, 0, 7},
+			{-1, 1, 3}, {0, 1, 5}, {1, 1, 1},
+		},
+		Divisor:    16,
+		Serpentine: true,
+	}
+	Atkinson = ErrorDiffusion{
+		Kernel: []Tap{
+			{1, 0, 1}, {2, 0, 1},
+			{-1, 1, 1}, {0, 1, 1}, {1, 1, 1},
+			{0, 2, 1},
+		},
+		Divisor:    8,
+		Serpentine: true,
+	}
+	JarvisJudiceNinke = ErrorDiffusion{
+		Kernel: []Tap{
+			{1, 0, 7}, {2, 0, 5},
+			{-2, 1, 3}, {-1, 1, 5}, {0, 1, 7}, {1, 1, 5}, {2, 1, 3},
+			{-2, 2, 1}, {-1, 2, 3}, {0, 2, 5}, {1, 2, 3}, {2, 2, 1},
+		},
+		Divisor:    48,
+		Serpentine: true,
+	}
+	Stucki = ErrorDiffusion{
+		Kernel: []Tap{
+			{1, 0, 8}, {2, 0, 4},
+			{-2, 1, 2}, {-1, 1, 4}, {0, 1, 8}, {1, 1, 4}, {2, 1, 2},
+			{-2, 2, 1}, {-1, 2, 2}, {0, 2, 4}, {1, 2, 2}, {2, 2, 1},
+		},
+		Divisor:    42,
+		Serpentine: true,
+	}
+	Burkes = ErrorDiffusion{
+		Kernel: []Tap{
+			{1, 0, 8}, {2, 0, 4},
+			{-2, 1, 2}, {-1, 1, 4}, {0, 1, 8}, {1, 1, 4}, {2, 1, 2},
+		},
+		Divisor:    32,
+		Serpentine: true,
+	}
+	Sierra = ErrorDiffusion{
+		Kernel: []Tap{
+			{1, 0, 5}, {2, 0, 3},
+			{-2, 1, 2}, {-1, 1, 4}, {0, 1, 5}, {1, 1, 4}, {2, 1, 2},
+			{-1, 2, 2}, {0, 2, 3}, {1, 2, 2},
+		},
+		Divisor:    32,
+		Serpentine: true,
+	}
+)
+
+func (d ErrorDiffusion) Dither(g *image.Gray) {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	if w == 0 || h == 0 {
+		return
+	}
+	depth := 0
+	for _, t := range d.Kernel {
+		if t.DY > depth {
+			depth = t.DY
+		}
+	}
+	// Error for the current row and the rows below it, kept in a ring.
+	rows := make([][]float64, depth+1)
+	for i := range rows {
+		rows[i] = make([]float64, w)
+	}
+	for y := 0; y < h; y++ {
+		cur := rows[y%len(rows)]
+		pix := g.Pix[y*g.Stride : y*g.Stride+w]
+		x, step := 0, 1
+		if d.Serpentine && y%2 == 1 {
+			x, step = w-1, -1
+		}
+		for ; x >= 0 && x < w; x += step {
+			v := float64(pix[x]) + cur[x]
+			out := 0.0
+			if v >= 128 {
+				out = 255
+			}
+			pix[x] = uint8(out)
+			e := (v - out) / d.Divisor
+			for _, t := range d.Kernel {
+				tx, ty := x+t.DX*step, y+t.DY
+				if tx < 0 || tx >= w || ty >= h {
+					continue
+				}
+				rows[ty%len(rows)][tx] += e * t.Weight
+			}
+		}
+		for i := range cur {
+			cur[i] = 0
+		}
+	}
+}
@@ -0,0 +1,84 @@
+package dither
+
+import (
+	"fmt"
+	"image"
+	"testing"
+)
+
+func gray(w, h int, pix ...uint8) *image.Gray {
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	copy(g.Pix, pix)
+	return g
+}
+
+func TestErrorDiffusionSerpentine(t *testing.T) {
+	right := []Tap{{1, 0, 1}}
+	belowRight := []Tap{{1, 1, 1}}
+	tests := []struct {
+		name       string
+		kernel     []Tap
+		serpentine bool
+		in, want   []uint8
+	}{
+		{
+			// All the error goes to the next pixel in scan order.
+			"raster", right, false,
+			[]uint8{60, 100, 200, 60, 100, 200},
+			[]uint8{0, 255, 0, 0, 255, 0},
+		},
+		{
+			"serpentine", right, true,
+			[]uint8{60, 100, 200, 60, 100, 200},
+			[]uint8{0, 255, 0, 0, 0, 255},
+		},
+		{
+			// Right to left, the tap below right falls below left,
+			// inside the image.
+			"mirrored", belowRight, true,
+			[]uint8{0, 0, 0, 0, 0, 200, 0, 150, 150},
+			[]uint8{0, 0, 0, 0, 0, 255, 0, 0, 255},
+		},
+		{
+			"not mirrored", belowRight, false,
+			[]uint8{0, 0, 0, 0, 0, 200, 0, 150, 150},
+			[]uint8{0, 0, 0, 0, 0, 255, 0, 255, 255},
+		},
+	}
+	for _, tt := range tests {
+		w := len(tt.in) / 2
+		if tt.kernel[0].DY == 1 {
+			w = 3
+		}
+		g := gray(w, len(tt.in)/w, tt.in...)
+		ErrorDiffusion{Kernel: tt.kernel, Divisor: 1, Serpentine: tt.serpentine}.Dither(g)
+		if fmt.Sprint(g.Pix) != fmt.Sprint(tt.want) {
+			t.Errorf("%s: %v, want %v", tt.name, g.Pix, tt.want)
+		}
+	}
+}
+
+func TestErrorDiffusionKeepsTone(t *testing.T) {
+	for name, d := range map[string]ErrorDiffusion{
+		"FloydSteinberg":    FloydSteinberg,
+		"JarvisJudiceNinke": JarvisJudiceNinke,
+		"Stucki":            Stucki,
+		"Burkes":            Burkes,
+		"Sierra":            Sierra,
+	} {
+		for _, v := range []uint8{32, 64, 128, 192} {
+			g := flat(64, 64, v)
+			d.Dither(g)
+			white := 0
+			for _, p := range g.Pix {
+				if p == 255 {
+					white++
+				}
+			}
+			got, want := float64(white)/float64(len(g.Pix)), float64(v)/255
+			if got < want-0.02 || got > want+0.02 {
+				t.Errorf("%s at level %d: %.3f white, want %.3f", name, v, got, want)
+			}
+		}
+	}
+}
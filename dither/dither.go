@@ -0,0 +1,83 @@
+// Package dither reduces grayscale images to the pure black and white
+// that a thermal print head can produce.
+//
+// Every Ditherer rewrites the pixels of an *image.Gray in place so that
+// each one is exactly 0 (black dot) or 255 (bare paper).
+package dither
+
+import "image"
+
+// Ditherer converts a grayscale image to black and white in place.
+type Ditherer interface {
+	Dither(g *image.Gray)
+}
+
+// Threshold makes every pixel at or above the given level white and
+// everything below it black.
+type Threshold uint8
+
+func (t Threshold) Dither(g *image.Gray) {
+	each(g, func(x, y int, v uint8) uint8 {
+		return binary(v >= uint8(t))
+	})
+}
+
+// Otsu thresholds each image at the level that best separates its
+// dark and light pixels, which suits line art, text and logos.
+var Otsu Ditherer = otsu{}
+
+type otsu struct{}
+
+func (otsu) Dither(g *image.Gray) {
+	var hist [256]int
+	each(g, func(x, y int, v uint8) uint8 {
+		hist[v]++
+		return v
+	})
+	Threshold(otsuLevel(hist)).Dither(g)
+}
+
+// otsuLevel returns the lowest level of the light class that
+// maximises the between-class variance.
+func otsuLevel(hist [256]int) int {
+	total, sum := 0, 0.0
+	for v, n := range hist {
+		total += n
+		sum += float64(v * n)
+	}
+	best, level := -1.0, 128
+	dark, darkSum := 0, 0.0
+	for t := 0; t < 255; t++ {
+		dark += hist[t]
+		darkSum += float64(t * hist[t])
+		light := total - dark
+		if dark == 0 || light == 0 {
+			continue
+		}
+		md := darkSum / float64(dark)
+		ml := (sum - darkSum) / float64(light)
+		between := float64(dark) * float64(light) * (md - ml) * (md - ml)
+		if between > best {
+			best, level = between, t+1
+		}
+	}
+	return level
+}
+
+// each replaces every pixel of g with f's result.
+func each(g *image.Gray, f func(x, y int, v uint8) uint8) {
+	w, h := g.Rect.Dx(), g.Rect.Dy()
+	for y := 0; y < h; y++ {
+		row := g.Pix[y*g.Stride : y*g.Stride+w]
+		for x, v := range row {
+			row[x] = f(x, y, v)
+		}
+	}
+}
+
+func binary(white bool) uint8 {
+	if white {
+		return 255
+	}
+	return 0
+}
@@ -0,0 +1,105 @@
+package dither
+
+import (
+	"flag"
+	"image"
+	"image/color"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite bluenoise.go")
+
+func flat(w, h int, v uint8) *image.Gray {
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range g.Pix {
+		g.Pix[i] = v
+	}
+	return g
+}
+
+var ditherers = map[string]Ditherer{
+	"Threshold":         Threshold(128),
+	"Otsu":              Otsu,
+	"Bayer2":            Bayer2,
+	"Bayer4":            Bayer4,
+	"Bayer8":            Bayer8,
+	"BlueNoise":         BlueNoise,
+	"FloydSteinberg":    FloydSteinberg,
+	"Atkinson":          Atkinson,
+	"JarvisJudiceNinke": JarvisJudiceNinke,
+	"Stucki":            Stucki,
+	"Burkes":            Burkes,
+	"Sierra":            Sierra,
+	"raster":            ErrorDiffusion{Kernel: FloydSteinberg.Kernel, Divisor: 16},
+}
+
+func TestDitherOutput(t *testing.T) {
+	for name, d := range ditherers {
+		// A gradient inside a larger image, which must not be touched.
+		outer := flat(80, 40, 77)
+		g := outer.SubImage(image.Rect(8, 4, 72, 36)).(*image.Gray)
+		for y := 0; y < 32; y++ {
+			for x := 0; x < 64; x++ {
+				g.SetGray(8+x, 4+y, color.Gray{uint8(x * 255 / 63)})
+			}
+		}
+		d.Dither(g)
+		for y := 0; y < 40; y++ {
+			for x := 0; x < 80; x++ {
+				v := outer.GrayAt(x, y).Y
+				inside := image.Pt(x, y).In(g.Rect)
+				if inside && v != 0 && v != 255 {
+					t.Errorf("%s: pixel (%d, %d) = %d, want 0 or 255", name, x, y, v)
+				}
+				if !inside && v != 77 {
+					t.Errorf("%s: pixel (%d, %d) outside the image changed to %d", name, x, y, v)
+				}
+			}
+		}
+
+		for _, v := range []uint8{0, 255} {
+			g := flat(16, 16, v)
+			d.Dither(g)
+			for i, p := range g.Pix {
+				if p != v {
+					t.Errorf("%s: solid %d has %d at %d", name, v, p, i)
+					break
+				}
+			}
+		}
+		d.Dither(image.NewGray(image.Rectangle{}))
+	}
+}
+
+func TestOtsuLevel(t *testing.T) {
+	hist := func(counts map[int]int) (h [256]int) {
+		for v, n := range counts {
+			h[v] = n
+		}
+		return h
+	}
+	tests := []struct {
+		name string
+		hist [256]int
+		want int
+	}{
+		{"two levels", hist(map[int]int{50: 100, 200: 100}), 51},
+		{"black and white", hist(map[int]int{0: 10, 255: 1000}), 1},
+		{"uneven", hist(map[int]int{20: 300, 40: 300, 180: 100, 240: 300}), 41},
+		{"one level", hist(map[int]int{90: 100}), 128},
+		{"empty", hist(nil), 128},
+	}
+	for _, tt := range tests {
+		if got := otsuLevel(tt.hist); got != tt.want {
+			t.Errorf("%s: otsuLevel = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestOtsu(t *testing.T) {
+	g := gray(4, 1, 50, 60, 190, 200)
+	Otsu.Dither(g)
+	if want := []uint8{0, 0, 255, 255}; string(g.Pix) != string(want) {
+		t.Errorf("Otsu = %v, want %v", g.Pix, want)
+	}
+}
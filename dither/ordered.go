@@ -0,0 +1,54 @@
+package dither
+
+import "image"
+
+// Ordered compares each pixel with a threshold taken from a matrix
+// tiled over the image. It needs no scan order, so neighbouring pixels
+// never influence each other and flat areas print as a steady texture.
+type Ordered struct {
+	n    int
+	rank []int // position of each matrix cell in the fill order
+}
+
+// Bayer2, Bayer4 and Bayer8 dither with Bayer matrices of that size,
+// giving the classic crosshatch look.
+var (
+	Bayer2 = bayer(2)
+	Bayer4 = bayer(4)
+	Bayer8 = bayer(8)
+)
+
+// BlueNoise is an ordered ditherer whose 64x64 matrix spreads dots as
+// evenly as possible without any visible pattern. The matrix was built
+// with the void-and-cluster method, which is too slow to run on a
+// small board at startup, and is stored in bluenoise.go.
+var BlueNoise Ditherer = &Ordered{n: blueNoiseSize, rank: blueNoiseRank}
+
+const blueNoiseSize = 64
+
+func (o *Ordered) Dither(g *image.Gray) {
+	levels := float64(len(o.rank))
+	each(g, func(x, y int, v uint8) uint8 {
+		r := o.rank[(y%o.n)*o.n+x%o.n]
+		return binary(float64(v) >= (float64(r)+0.5)*255/levels)
+	})
+}
+
+// bayer returns the n x n Bayer matrix, n being a power of two.
+func bayer(n int) *Ordered {
+	rank := []int{0}
+	for size := 1; size < n; size *= 2 {
+		next := make([]int, 4*size*size)
+		for y := 0; y < size; y++ {
+			for x := 0; x < size; x++ {
+				r := 4 * rank[y*size+x]
+				next[y*2*size+x] = r
+				next[y*2*size+x+size] = r + 2
+				next[(y+size)*2*size+x] = r + 3
+				next[(y+size)*2*size+x+size] = r + 1
+			}
+		}
+		rank = next
+	}
+	return &Ordered{n: n, rank: rank}
+}
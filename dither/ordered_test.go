@@ -0,0 +1,193 @@
+package dither
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"math"
+	"math/rand"
+	"os"
+	"testing"
+)
+
+func TestBayer(t *testing.T) {
+	tests := []struct {
+		o    *Ordered
+		want []int
+	}{
+		{Bayer2, []int{
+			0, 2,
+			3, 1,
+		}},
+		{Bayer4, []int{
+			0, 8, 2, 10,
+			12, 4, 14, 6,
+			3, 11, 1, 9,
+			15, 7, 13, 5,
+		}},
+	}
+	for _, tt := range tests {
+		if fmt.Sprint(tt.o.rank) != fmt.Sprint(tt.want) {
+			t.Errorf("Bayer%d = %v, want %v", tt.o.n, tt.o.rank, tt.want)
+		}
+	}
+	// Each quadrant of a Bayer matrix repeats the one half its size,
+	// offset as in Bayer2.
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			want := 4*Bayer4.rank[(y%4)*4+x%4] + Bayer2.rank[(y/4)*2+x/4]
+			if got := Bayer8.rank[y*8+x]; got != want {
+				t.Errorf("Bayer8 at (%d, %d) = %d, want %d", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestOrderedLevels(t *testing.T) {
+	for _, o := range []*Ordered{Bayer2, Bayer4, Bayer8, BlueNoise.(*Ordered)} {
+		if !isPermutation(o.rank) {
+			t.Errorf("%dx%d matrix is not a ranking of its cells", o.n, o.n)
+			continue
+		}
+		levels := len(o.rank)
+		for _, v := range []int{0, 1, 64, 128, 200, 254, 255} {
+			g := flat(o.n, o.n, uint8(v))
+			o.Dither(g)
+			// Cells ranked below v/255 of the levels turn white.
+			for i, p := range g.Pix {
+				white := float64(o.rank[i])+0.5 <= float64(v)*float64(levels)/255
+				if p != binary(white) {
+					t.Errorf("%dx%d matrix at level %d: cell %d = %d", o.n, o.n, v, i, p)
+					break
+				}
+			}
+		}
+	}
+}
+
+func isPermutation(rank []int) bool {
+	seen := make([]bool, len(rank))
+	for _, r := range rank {
+		if r < 0 || r >= len(rank) || seen[r] {
+			return false
+		}
+		seen[r] = true
+	}
+	return true
+}
+
+// TestBlueNoiseTable checks bluenoise.go against the void-and-cluster
+// method, and rewrites it with -update.
+func TestBlueNoiseTable(t *testing.T) {
+	rank := voidAndCluster(blueNoiseSize)
+	if *update {
+		var buf bytes.Buffer
+		fmt.Fprintf(&buf, "// Code generated by \"go test -run TestBlueNoiseTable -update\"; DO NOT EDIT.\n\n")
+		fmt.Fprintf(&buf, "package dither\n\n")
+		fmt.Fprintf(&buf, "// blueNoiseRank is the %dx%d matrix of BlueNoise, row by row.\n", blueNoiseSize, blueNoiseSize)
+		fmt.Fprintf(&buf, "var blueNoiseRank = []int{\n")
+		for i := 0; i < len(rank); i += 16 {
+			for _, r := range rank[i : i+16] {
+				fmt.Fprintf(&buf, "%d, ", r)
+			}
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+		src, err := format.Source(buf.Bytes())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile("bluenoise.go", src, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	if fmt.Sprint(rank) != fmt.Sprint(blueNoiseRank) {
+		t.Error("bluenoise.go is out of date; run go test -run TestBlueNoiseTable -update")
+	}
+}
+
+// voidAndCluster ranks the cells of an n x n torus so that the first k
+// cells of the ranking form an evenly spread pattern for every k.
+func voidAndCluster(n int) []int {
+	size := n * n
+	const sigma = 1.5
+	kernel := make([]float64, size)
+	for dy := 0; dy < n; dy++ {
+		for dx := 0; dx < n; dx++ {
+			wx, wy := math.Min(float64(dx), float64(n-dx)), math.Min(float64(dy), float64(n-dy))
+			kernel[dy*n+dx] = math.Exp(-(wx*wx + wy*wy) / (2 * sigma * sigma))
+		}
+	}
+
+	dots := make([]bool, size)
+	energy := make([]float64, size)
+	toggle := func(p int, on bool) {
+		dots[p] = on
+		s := 1.0
+		if !on {
+			s = -1
+		}
+		px, py := p%n, p/n
+		for q := range energy {
+			dx, dy := (q%n-px+n)%n, (q/n-py+n)%n
+			energy[q] += s * kernel[dy*n+dx]
+		}
+	}
+	// tightest finds the densest dot, largestVoid the emptiest gap.
+	tightest := func() int {
+		best := -1
+		for p, on := range dots {
+			if on && (best < 0 || energy[p] > energy[best]) {
+				best = p
+			}
+		}
+		return best
+	}
+	largestVoid := func() int {
+		best := -1
+		for p, on := range dots {
+			if !on && (best < 0 || energy[p] < energy[best]) {
+				best = p
+			}
+		}
+		return best
+	}
+
+	// Start from a fixed random pattern so the matrix is reproducible,
+	// then move dots from clusters to voids until nothing changes.
+	rng := rand.New(rand.NewSource(1))
+	initial := size / 10
+	for count := 0; count < initial; {
+		if p := rng.Intn(size); !dots[p] {
+			toggle(p, true)
+			count++
+		}
+	}
+	for i := 0; i < size; i++ {
+		c := tightest()
+		toggle(c, false)
+		v := largestVoid()
+		toggle(v, true)
+		if v == c {
+			break
+		}
+	}
+
+	rank := make([]int, size)
+	savedDots := append([]bool(nil), dots...)
+	savedEnergy := append([]float64(nil), energy...)
+	for r := initial - 1; r >= 0; r-- {
+		c := tightest()
+		toggle(c, false)
+		rank[c] = r
+	}
+	copy(dots, savedDots)
+	copy(energy, savedEnergy)
+	for r := initial; r < size; r++ {
+		v := largestVoid()
+		toggle(v, true)
+		rank[v] = r
+	}
+	return rank
+}
@@ -0,0 +1,172 @@
+package emulator
+
+import (
+	"strings"
+)
+
+// Barcode types accepted by GS k (same numbering as thermalprinter).
+const (
+	barcodeUPCA = iota
+	barcodeUPCE
+	barcodeEAN13
+	barcodeEAN8
+	barcodeCODE39
+)
+
+var code39 = map[byte]string{
+	'0': "nnnwwnwnn", '1': "wnnwnnnnw", '2': "nnwwnnnnw", '3': "wnwwnnnnn",
+	'4': "nnnwwnnnw", '5': "wnnwwnnnn", '6': "nnwwwnnnn", '7': "nnnwnnwnw",
+	'8': "wnnwnnwnn", '9': "nnwwnnwnn", 'A': "wnnnnwnnw", 'B': "nnwnnwnnw",
+	'C': "wnwnnwnnn", 'D': "nnnnwwnnw", 'E': "wnnnwwnnn", 'F': "nnwnwwnnn",
+	'G': "nnnnnwwnw", 'H': "wnnnnwwnn", 'I': "nnwnnwwnn", 'J': "nnnnwwwnn",
+	'K': "wnnnnnnww", 'L': "nnwnnnnww", 'M': "wnwnnnnwn", 'N': "nnnnwnnww",
+	'O': "wnnnwnnwn", 'P': "nnwnwnnwn", 'Q': "nnnnnnwww", 'R': "wnnnnnwwn",
+	'S': "nnwnnnwwn", 'T': "nnnnwnwwn", 'U': "wwnnnnnnw", 'V': "nwwnnnnnw",
+	'W': "wwwnnnnnn", 'X': "nwnnwnnnw", 'Y': "wwnnwnnnn", 'Z': "nwwnwnnnn",
+	'-': "nwnnnnwnw", '.': "wwnnnnwnn", ' ': "nwwnnnwnn", '$': "nwnwnwnnn",
+	'/': "nwnwnnnwn", '+': "nwnnnwnwn", '%': "nnnwnwnwn", '*': "nwnnwnwnn",
+}
+
+var (
+	eanL = [10]string{
+		"0001101", "0011001", "0010011", "0111101", "0100011",
+		"0110001", "0101111", "0111011", "0110111", "0001011",
+	}
+	eanG = [10]string{
+		"0100111", "0110011", "0011011", "0100001", "0011101",
+		"0111001", "0000101", "0010001", "0001001", "0010111",
+	}
+	eanR = [10]string{
+		"1110010", "1100110", "1101100", "1000010", "1011100",
+		"1001110", "1010000", "1000100", "1001000", "1110100",
+	}
+	ean13Parity = [10]string{
+		"LLLLLL", "LLGLGG", "LLGGLG", "LLGGGL", "LGLLGG",
+		"LGGLLG", "LGGGLL", "LGLGLG", "LGLGGL", "LGGLGL",
+	}
+)
+
+// barcodeModules returns the bar pattern of data as a string of
+// '1' (bar) and '0' (space) modules. ok is false when data is not
+// valid for the symbology and a generic pattern was produced instead.
+func barcodeModules(kind int, data string) (modules string, ok bool) {
+	switch kind {
+	case barcodeCODE39:
+		return code39Modules(data)
+	case barcodeUPCA:
+		if m, ok := eanModules("0"+data, 13); ok {
+			return m, true
+		}
+	case barcodeEAN13:
+		if m, ok := eanModules(data, 13); ok {
+			return m, true
+		}
+	case barcodeEAN8:
+		if m, ok := eanModules(data, 8); ok {
+			return m, true
+		}
+	}
+	return genericModules(data), false
+}
+
+func code39Modules(data string) (string, bool) {
+	var b strings.Builder
+	ok := true
+	for _, c := range []byte("*" + strings.ToUpper(data) + "*") {
+		pattern, found := code39[c]
+		if !found {
+			ok = false
+			continue
+		}
+		for i, e := range pattern {
+			bit := "1"
+			if i%2 == 1 {
+				bit = "0"
+			}
+			n := 1
+			if e == 'w' {
+				n = 3
+			}
+			b.WriteString(strings.Repeat(bit, n))
+		}
+		b.WriteString("0")
+	}
+	return b.String(), ok
+}
+
+// eanModules encodes an EAN-13 or EAN-8 number. The check digit is
+// computed when data is one digit short.
+func eanModules(data string, length int) (string, bool) {
+	for _, c := range data {
+		if c < '0' || c > '9' {
+			return "", false
+		}
+	}
+	if len(data) == length-1 {
+		data += string(rune('0' + eanCheckDigit(data)))
+	}
+	if len(data) != length {
+		return "", false
+	}
+	digits := make([]int, length)
+	for i := range data {
+		digits[i] = int(data[i] - '0')
+	}
+
+	var b strings.Builder
+	b.WriteString("101")
+	if length == 13 {
+		parity := ean13Parity[digits[0]]
+		for i, d := range digits[1:7] {
+			if parity[i] == 'G' {
+				b.WriteString(eanG[d])
+			} else {
+				b.WriteString(eanL[d])
+			}
+		}
+		b.WriteString("01010")
+		for _, d := range digits[7:] {
+			b.WriteString(eanR[d])
+		}
+	} else {
+		for _, d := range digits[:4] {
+			b.WriteString(eanL[d])
+		}
+		b.WriteString("01010")
+		for _, d := range digits[4:] {
+			b.WriteString(eanR[d])
+		}
+	}
+	b.WriteString("101")
+	return b.String(), true
+}
+
+func eanCheckDigit(data string) int {
+	sum := 0
+	for i := len(data) - 1; i >= 0; i-- {
+		d := int(data[i] - '0')
+		if (len(data)-1-i)%2 == 0 {
+			d *= 3
+		}
+		sum += d
+	}
+	return (10 - sum%10) % 10
+}
+
+// genericModules draws each data byte as 8 modules between guard bars.
+// It is used for symbologies the emulator does not encode.
+func genericModules(data string) string {
+	var b strings.Builder
+	b.WriteString("101")
+	for _, c := range []byte(data) {
+		for bit := 7; bit >= 0; bit-- {
+			if c&(1<<uint(bit)) != 0 {
+				b.WriteString("1")
+			} else {
+				b.WriteString("0")
+			}
+		}
+	}
+	b.WriteString("101")
+	return b.String()
+}
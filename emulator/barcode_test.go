@@ -0,0 +1,85 @@
+package emulator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEANCheckDigit(t *testing.T) {
+	for _, tt := range []struct {
+		data string
+		want int
+	}{
+		{"400638133393", 1}, // EAN-13 4006381333931
+		{"9638507", 4},      // EAN-8 96385074
+		{"003600029145", 2}, // UPC-A 036000291452
+		{"000000000000", 0},
+	} {
+		if got := eanCheckDigit(tt.data); got != tt.want {
+			t.Errorf("eanCheckDigit(%q) = %d, want %d", tt.data, got, tt.want)
+		}
+	}
+}
+
+func TestBarcodeModules(t *testing.T) {
+	ean8 := "101" + "0001011" + "0101111" + "0111101" + "0110111" +
+		"01010" + "1001110" + "1110010" + "1000100" + "1011100" + "101"
+	tests := []struct {
+		kind int
+		data string
+		want string
+	}{
+		{barcodeEAN8, "9638507", ean8},
+		{barcodeEAN8, "96385074", ean8},
+		// The check digit is computed, not read.
+		{barcodeEAN13, "4006381333931", mustModules(t, barcodeEAN13, "400638133393")},
+		{barcodeUPCA, "03600029145", mustModules(t, barcodeEAN13, "003600029145")},
+		// Start character, A, B, stop character, each followed by a
+		// narrow gap.
+		{barcodeCODE39, "ab", "1000101110111010" + "1110101000101110" + "1011101000101110" + "1000101110111010"},
+	}
+	for _, tt := range tests {
+		got, ok := barcodeModules(tt.kind, tt.data)
+		if !ok || got != tt.want {
+			t.Errorf("barcodeModules(%d, %q) = %s, %v, want %s", tt.kind, tt.data, got, ok, tt.want)
+		}
+	}
+
+	m := mustModules(t, barcodeEAN13, "400638133393")
+	if len(m) != 95 || !strings.HasPrefix(m, "101") || !strings.HasSuffix(m, "101") || m[45:50] != "01010" {
+		t.Errorf("EAN-13 modules %s lack the 95 module layout", m)
+	}
+}
+
+func TestBarcodeModulesInvalid(t *testing.T) {
+	for _, tt := range []struct {
+		kind int
+		data string
+	}{
+		{barcodeEAN13, "12a"},
+		{barcodeEAN13, "12345"},
+		{barcodeEAN8, "123456789"},
+		{barcodeCODE39, "a_b"},
+		{9, "12"}, // CODE128 is not encoded
+	} {
+		got, ok := barcodeModules(tt.kind, tt.data)
+		if ok {
+			t.Errorf("barcodeModules(%d, %q) succeeded", tt.kind, tt.data)
+		}
+		if got == "" {
+			t.Errorf("barcodeModules(%d, %q) drew no placeholder", tt.kind, tt.data)
+		}
+	}
+	if got := genericModules("A"); got != "101"+"01000001"+"101" {
+		t.Errorf("genericModules(\"A\") = %s", got)
+	}
+}
+
+func mustModules(t *testing.T, kind int, data string) string {
+	t.Helper()
+	m, ok := barcodeModules(kind, data)
+	if !ok {
+		t.Fatalf("barcodeModules(%d, %q) failed", kind, data)
+	}
+	return m
+}
@@ -0,0 +1,713 @@
+// Package emulator is a software CSN-A2 printer. It consumes the
+// ESC/POS byte stream produced by thermalprinter.Printer and renders
+// the printed receipt to an image.
+package emulator
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Width of the print head in dots.
+const Width = 384
+
+// Print mode bits of ESC ! (same layout as thermalprinter).
+const (
+	inverseMask      = 1 << 1
+	updownMask       = 1 << 2
+	boldMask         = 1 << 3
+	doubleHeightMask = 1 << 4
+	doubleWidthMask  = 1 << 5
+	strikeMask       = 1 << 6
+)
+
+const (
+	defaultLineSpacing   = 32
+	defaultBarcodeHeight = 50
+	defaultBarcodeWidth  = 3
+	hriBelow             = 2
+)
+
+var ErrClosed = errors.New("emulator: closed")
+
+// Diagnostic describes an unknown or malformed command found in the
+// byte stream. Offset is the position of its first byte.
+type Diagnostic struct {
+	Offset  int
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("offset %d: %s", d.Offset, d.Message)
+}
+
+type cell struct {
+	char      byte
+	mode      byte
+	scaleX    int
+	scaleY    int
+	underline int
+}
+
+func (c cell) width() int {
+	return CharWidth * c.scaleX
+}
+
+func (c cell) height() int {
+	return CharHeight * c.scaleY
+}
+
+// Emulator implements thermalprinter.Transport. Status queries are
+// answered through Read.
+type Emulator struct {
+	mu sync.Mutex
+
+	pending []byte // bytes of an incomplete command
+	offset  int    // stream offset of pending[0]
+	closed  bool
+
+	rows    [][]bool
+	line    []cell
+	diags   []Diagnostic
+	replies []byte // status replies waiting to be read
+
+	paperOut     bool
+	paperNearEnd bool
+	coverOpen    bool
+
+	online        bool
+	printMode     byte
+	charSize      byte
+	justify       int
+	lineSpacing   int
+	underline     int
+	barcodeHeight int
+	barcodeWidth  int
+	hriPosition   int
+}
+
+func New() *Emulator {
+	e := &Emulator{}
+	e.reset()
+	return e
+}
+
+func (e *Emulator) reset() {
+	e.online = true
+	e.printMode = 0
+	e.charSize = 0
+	e.justify = 0
+	e.lineSpacing = defaultLineSpacing
+	e.underline = 0
+	e.barcodeHeight = defaultBarcodeHeight
+	e.barcodeWidth = defaultBarcodeWidth
+	e.hriPosition = 0
+}
+
+func (e *Emulator) Write(data []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return 0, ErrClosed
+	}
+	e.pending = append(e.pending, data...)
+	e.parse(false)
+	return len(data), nil
+}
+
+// Read returns the replies to status queries written so far, or
+// io.EOF when there are none, like a serial port whose read timed out.
+func (e *Emulator) Read(data []byte) (int, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return 0, ErrClosed
+	}
+	if len(e.replies) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(data, e.replies)
+	e.replies = e.replies[n:]
+	return n, nil
+}
+
+// SetPaper sets what the paper sensors report.
+func (e *Emulator) SetPaper(present, nearEnd bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.paperOut = !present
+	e.paperNearEnd = nearEnd
+}
+
+// SetCoverOpen sets what the cover sensor reports.
+func (e *Emulator) SetCoverOpen(open bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.coverOpen = open
+}
+
+// Close ends the stream. A command cut short by the end of the stream
+// is reported as a diagnostic.
+func (e *Emulator) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		return ErrClosed
+	}
+	e.parse(true)
+	e.closed = true
+	return nil
+}
+
+// Image returns the receipt printed so far. Text that has not been
+// terminated by a line feed is not printed yet, as on the real device.
+func (e *Emulator) Image() image.Image {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	img := image.NewGray(image.Rect(0, 0, Width, len(e.rows)))
+	for y, row := range e.rows {
+		for x, dot := range row {
+			if dot {
+				img.SetGray(x, y, color.Gray{Y: 0})
+			} else {
+				img.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return img
+}
+
+// Diagnostics returns the unknown or malformed commands seen so far.
+func (e *Emulator) Diagnostics() []Diagnostic {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.diags
+}
+
+func (e *Emulator) diagf(offset int, format string, args ...interface{}) {
+	e.diags = append(e.diags, Diagnostic{Offset: offset, Message: fmt.Sprintf(format, args...)})
+}
+
+// parse consumes every complete command in pending. When final is
+// set, the end of the stream terminates whatever is left.
+func (e *Emulator) parse(final bool) {
+	for len(e.pending) > 0 {
+		n := e.step(e.pending, final)
+		if n == 0 {
+			if final {
+				e.diagf(e.offset, "truncated command % x", e.pending)
+				e.offset += len(e.pending)
+				e.pending = nil
+			}
+			return
+		}
+		e.pending = e.pending[n:]
+		e.offset += n
+	}
+	e.pending = nil
+}
+
+// step executes the command at the start of b and returns the number
+// of bytes it took, or 0 when b does not hold the whole command yet.
+func (e *Emulator) step(b []byte, final bool) int {
+	switch c := b[0]; {
+	case c == 10: // LF
+		e.printLine()
+		return 1
+	case c == 12: // FF
+		if len(e.line) > 0 {
+			e.printLine()
+		}
+		return 1
+	case c == 13: // CR
+		return 1
+	case c == 255: // Wake
+		return 1
+	case c == 27:
+		return e.stepESC(b)
+	case c == 29:
+		return e.stepGS(b, final)
+	case c == 18:
+		return e.stepDC2(b)
+	case c == 16:
+		if len(b) < 2 {
+			return 0
+		}
+		if b[1] != 4 {
+			e.diagf(e.offset, "unknown command DLE 0x%02x", b[1])
+			return 2
+		}
+		if len(b) < 3 {
+			return 0
+		}
+		e.realTimeStatus(b[2])
+		return 3
+	case c < 0x20 || c == 0x7f:
+		e.diagf(e.offset, "unknown control byte 0x%02x", c)
+		return 1
+	default:
+		e.printChar(c)
+		return 1
+	}
+}
+
+func (e *Emulator) stepESC(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	}
+	// Commands with one parameter byte
+	arg := func() (byte, bool) {
+		if len(b) < 3 {
+			return 0, false
+		}
+		return b[2], true
+	}
+	switch b[1] {
+	case 27:
+		// Wake() sends a run of lone ESC bytes.
+		return 1
+	case '@':
+		e.line = nil
+		e.reset()
+		return 2
+	case '2':
+		e.lineSpacing = defaultLineSpacing
+		return 2
+	case '!':
+		n, ok := arg()
+		if !ok {
+			return 0
+		}
+		e.printMode = n
+		return 3
+	case 'a':
+		n, ok := arg()
+		if !ok {
+			return 0
+		}
+		switch n {
+		case 0, 1, 2:
+			e.justify = int(n)
+		case '0', '1', '2':
+			e.justify = int(n - '0')
+		default:
+			e.diagf(e.offset, "ESC a: invalid justification %d", n)
+		}
+		return 3
+	case '3':
+		n, ok := arg()
+		if !ok {
+			return 0
+		}
+		e.lineSpacing = int(n)
+		return 3
+	case '-':
+		n, ok := arg()
+		if !ok {
+			return 0
+		}
+		switch n {
+		case 0, 1, 2:
+			e.underline = int(n)
+		case '0', '1', '2':
+			e.underline = int(n - '0')
+		default:
+			e.diagf(e.offset, "ESC -: invalid underline weight %d", n)
+		}
+		return 3
+	case '=':
+		n, ok := arg()
+		if !ok {
+			return 0
+		}
+		e.online = n&1 != 0
+		return 3
+	case '8':
+		if _, ok := arg(); !ok {
+			return 0
+		}
+		return 3
+	case 'J':
+		n, ok := arg()
+		if !ok {
+			return 0
+		}
+		if len(e.line) > 0 {
+			e.printLine()
+		}
+		e.feed(int(n))
+		return 3
+	case 'd':
+		n, ok := arg()
+		if !ok {
+			return 0
+		}
+		for i := 0; i < int(n); i++ {
+			e.printLine()
+		}
+		return 3
+	case 'v':
+		if _, ok := arg(); !ok {
+			return 0
+		}
+		var status byte
+		if e.paperOut {
+			status |= 0x04
+		}
+		e.replies = append(e.replies, status)
+		return 3
+	case '7':
+		if len(b) < 5 {
+			return 0
+		}
+		return 5
+	default:
+		e.diagf(e.offset, "unknown command ESC 0x%02x", b[1])
+		return 2
+	}
+}
+
+func (e *Emulator) stepGS(b []byte, final bool) int {
+	if len(b) < 3 {
+		if len(b) == 2 && b[1] == 'k' && final {
+			e.diagf(e.offset, "GS k: missing barcode type")
+			return 2
+		}
+		return 0
+	}
+	n := b[2]
+	switch b[1] {
+	case '!':
+		e.charSize = n
+		return 3
+	case 'H':
+		e.hriPosition = int(n)
+		return 3
+	case 'h':
+		e.barcodeHeight = int(n)
+		return 3
+	case 'w':
+		if n < 2 || n > 6 {
+			e.diagf(e.offset, "GS w: invalid barcode width %d", n)
+		} else {
+			e.barcodeWidth = int(n)
+		}
+		return 3
+	case 'r':
+		var status byte
+		if n == 1 || n == '1' {
+			if e.paperNearEnd {
+				status |= 0x03
+			}
+			if e.paperOut {
+				status |= 0x0c
+			}
+		}
+		e.replies = append(e.replies, status)
+		return 3
+	case 'k':
+		return e.stepBarcode(b, final)
+	default:
+		e.diagf(e.offset, "unknown command GS 0x%02x", b[1])
+		return 2
+	}
+}
+
+// stepBarcode handles both forms of GS k: "GS k m d1...dk NUL" for
+// m < 65 and "GS k m n d1...dn" otherwise. The first form is also
+// ended by any control byte, which Printer relies on by following
+// the data with a line feed.
+func (e *Emulator) stepBarcode(b []byte, final bool) int {
+	kind := int(b[2])
+	if kind >= 65 {
+		if len(b) < 4 {
+			return 0
+		}
+		n := int(b[3])
+		if len(b) < 4+n {
+			return 0
+		}
+		e.printBarcode(kind-65, string(b[4:4+n]))
+		return 4 + n
+	}
+	for i := 3; i < len(b); i++ {
+		if b[i] == 0 {
+			e.printBarcode(kind, string(b[3:i]))
+			return i + 1
+		}
+		if b[i] < 0x20 {
+			e.printBarcode(kind, string(b[3:i]))
+			return i
+		}
+	}
+	if !final {
+		return 0
+	}
+	e.diagf(e.offset, "GS k: barcode data not terminated")
+	e.printBarcode(kind, string(b[3:]))
+	return len(b)
+}
+
+func (e *Emulator) stepDC2(b []byte) int {
+	if len(b) < 2 {
+		return 0
+	}
+	switch b[1] {
+	case '#':
+		if len(b) < 3 {
+			return 0
+		}
+		return 3
+	case '*':
+		if len(b) < 4 {
+			return 0
+		}
+		rows, rowBytes := int(b[2]), int(b[3])
+		if len(b) < 4+rows*rowBytes {
+			return 0
+		}
+		if rowBytes*8 > Width {
+			e.diagf(e.offset, "DC2 *: row of %d bytes exceeds print width", rowBytes)
+		}
+		e.printBitmap(rows, rowBytes, b[4:4+rows*rowBytes])
+		return 4 + rows*rowBytes
+	default:
+		e.diagf(e.offset, "unknown command DC2 0x%02x", b[1])
+		return 2
+	}
+}
+
+// realTimeStatus answers DLE EOT n.
+func (e *Emulator) realTimeStatus(n byte) {
+	status := byte(0x12)
+	switch n {
+	case 1:
+		if !e.online {
+			status |= 0x08
+		}
+	case 2:
+		if e.coverOpen {
+			status |= 0x04
+		}
+		if e.paperOut {
+			status |= 0x20
+		}
+	case 3:
+	case 4:
+		if e.paperNearEnd {
+			status |= 0x0c
+		}
+		if e.paperOut {
+			status |= 0x60
+		}
+	default:
+		e.diagf(e.offset, "DLE EOT: invalid status type %d", n)
+		return
+	}
+	e.replies = append(e.replies, status)
+}
+
+func (e *Emulator) feed(rows int) {
+	for i := 0; i < rows; i++ {
+		e.rows = append(e.rows, make([]bool, Width))
+	}
+}
+
+// block appends h blank rows and returns them for drawing.
+func (e *Emulator) block(h int) [][]bool {
+	start := len(e.rows)
+	e.feed(h)
+	return e.rows[start:]
+}
+
+func (e *Emulator) lineWidth() int {
+	w := 0
+	for _, c := range e.line {
+		w += c.width()
+	}
+	return w
+}
+
+func (e *Emulator) justifyOffset(w int) int {
+	switch e.justify {
+	case 1:
+		return (Width - w) / 2
+	case 2:
+		return Width - w
+	default:
+		return 0
+	}
+}
+
+func (e *Emulator) printChar(c byte) {
+	if !e.online {
+		return
+	}
+	ch := cell{char: c, mode: e.printMode, scaleX: 1, scaleY: 1, underline: e.underline}
+	if e.printMode&doubleWidthMask != 0 || e.charSize&0xf0 != 0 {
+		ch.scaleX = 2
+	}
+	if e.printMode&doubleHeightMask != 0 || e.charSize&0x0f != 0 {
+		ch.scaleY = 2
+	}
+	if c >= 0x80 {
+		ch.char = 0
+	}
+	if e.lineWidth()+ch.width() > Width {
+		e.printLine()
+	}
+	e.line = append(e.line, ch)
+}
+
+// printLine prints the line buffer and advances by the line spacing,
+// or by the tallest character when it does not fit.
+func (e *Emulator) printLine() {
+	if !e.online {
+		return
+	}
+	h := CharHeight
+	for _, c := range e.line {
+		if c.height() > h {
+			h = c.height()
+		}
+	}
+	pitch := e.lineSpacing
+	if pitch < h {
+		pitch = h
+	}
+	rows := e.block(pitch)
+
+	x := e.justifyOffset(e.lineWidth())
+	upsideDown := false
+	for _, c := range e.line {
+		drawCell(rows, x, h-c.height(), c)
+		if c.mode&updownMask != 0 {
+			upsideDown = true
+		}
+		x += c.width()
+	}
+	if upsideDown {
+		rotate(rows[:h])
+	}
+	e.line = nil
+}
+
+func drawCell(rows [][]bool, x0, y0 int, c cell) {
+	w, h := c.width(), c.height()
+	for y := 0; y < h; y++ {
+		gy := y / c.scaleY
+		for x := 0; x < w; x++ {
+			gx := x / c.scaleX
+			dot := glyphDot(c.char, gx, gy)
+			if c.mode&boldMask != 0 && gx > 0 {
+				dot = dot || glyphDot(c.char, gx-1, gy)
+			}
+			if c.mode&strikeMask != 0 && gy == CharHeight/2 {
+				dot = true
+			}
+			if c.underline > 0 && gy >= CharHeight-c.underline {
+				dot = true
+			}
+			if c.mode&inverseMask != 0 {
+				dot = !dot
+			}
+			set(rows, x0+x, y0+y, dot)
+		}
+	}
+}
+
+func set(rows [][]bool, x, y int, dot bool) {
+	if x < 0 || x >= Width || y < 0 || y >= len(rows) {
+		return
+	}
+	rows[y][x] = dot
+}
+
+func rotate(rows [][]bool) {
+	for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+		rows[i], rows[j] = rows[j], rows[i]
+	}
+	for _, row := range rows {
+		for i, j := 0, len(row)-1; i < j; i, j = i+1, j-1 {
+			row[i], row[j] = row[j], row[i]
+		}
+	}
+}
+
+func (e *Emulator) printBitmap(rows, rowBytes int, data []byte) {
+	if !e.online {
+		return
+	}
+	if len(e.line) > 0 {
+		e.printLine()
+	}
+	block := e.block(rows)
+	for y := 0; y < rows; y++ {
+		for i := 0; i < rowBytes; i++ {
+			b := data[y*rowBytes+i]
+			for bit := 0; bit < 8; bit++ {
+				set(block, i*8+bit, y, b&(0x80>>uint(bit)) != 0)
+			}
+		}
+	}
+}
+
+func (e *Emulator) printBarcode(kind int, data string) {
+	if !e.online {
+		return
+	}
+	if len(e.line) > 0 {
+		e.printLine()
+	}
+	modules, ok := barcodeModules(kind, data)
+	if !ok {
+		e.diagf(e.offset, "GS k: cannot encode %q as type %d, drawing placeholder", data, kind)
+	}
+
+	w := len(modules) * e.barcodeWidth
+	if w > Width {
+		e.diagf(e.offset, "GS k: barcode is %d dots wide, clipped to %d", w, Width)
+	}
+	x0 := e.justifyOffset(w)
+	if x0 < 0 {
+		x0 = 0
+	}
+
+	if e.hriPosition&1 != 0 {
+		e.printHRI(data)
+	}
+	rows := e.block(e.barcodeHeight)
+	for i, m := range modules {
+		if m != '1' {
+			continue
+		}
+		for x := 0; x < e.barcodeWidth; x++ {
+			for y := range rows {
+				set(rows, x0+i*e.barcodeWidth+x, y, true)
+			}
+		}
+	}
+	if e.hriPosition&hriBelow != 0 {
+		e.printHRI(data)
+	}
+}
+
+// printHRI prints the human readable text of a barcode.
+func (e *Emulator) printHRI(data string) {
+	data = strings.Map(func(r rune) rune {
+		if r < 0x20 || r > 0x7e {
+			return -1
+		}
+		return r
+	}, data)
+	rows := e.block(CharHeight)
+	x := e.justifyOffset(len(data) * CharWidth)
+	for i := 0; i < len(data); i++ {
+		drawCell(rows, x+i*CharWidth, 0, cell{char: data[i], scaleX: 1, scaleY: 1})
+	}
+}
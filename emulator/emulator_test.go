@@ -0,0 +1,475 @@
+package emulator
+
+import (
+	"bytes"
+	"flag"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden images in testdata")
+
+func stream(cmds ...[]byte) []byte {
+	return new(escpos.Builder).Add(cmds...).Bytes()
+}
+
+// render runs data through a new emulator and returns it closed.
+func render(t *testing.T, data []byte) *Emulator {
+	t.Helper()
+	e := New()
+	if _, err := e.Write(data); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
+
+// renderClean is render for streams that must not cause diagnostics.
+func renderClean(t *testing.T, data []byte) *Emulator {
+	t.Helper()
+	e := render(t, data)
+	if d := e.Diagnostics(); len(d) != 0 {
+		t.Errorf("diagnostics: %v", d)
+	}
+	return e
+}
+
+var goldenTests = []struct {
+	name string
+	data []byte
+}{
+	{"text", stream(
+		[]byte("Hello, world!\n"),
+		[]byte(" !\"#$%&'()*+,-./0123456789:;<=>?\n"),
+		[]byte("@ABCDEFGHIJKLMNOPQRSTUVWXYZ[\\]^_\n"),
+		[]byte("`abcdefghijklmnopqrstuvwxyz{|}~\x80\n"),
+		[]byte("This line is longer than thirty-two columns and wraps.\n"),
+	)},
+	{"modes", stream(
+		escpos.PrintMode(escpos.ModeBold), []byte("Bold\n"),
+		escpos.PrintMode(escpos.ModeInverse), []byte("Inverse\n"),
+		escpos.PrintMode(escpos.ModeStrike), []byte("Strike\n"),
+		escpos.PrintMode(0), escpos.Underline(1), []byte("Underline 1\n"),
+		escpos.Underline(2), []byte("Underline 2\n"),
+		escpos.Underline(0), escpos.PrintMode(escpos.ModeDoubleHeight), []byte("Double height\n"),
+		escpos.PrintMode(escpos.ModeDoubleWidth), []byte("Double width\n"),
+		escpos.PrintMode(0), []byte("Normal\n"),
+	)},
+	{"sizes", stream(
+		escpos.CharSize(0x00), []byte("Small\n"),
+		escpos.CharSize(0x01), []byte("Medium\n"),
+		escpos.CharSize(0x11), []byte("Large\n"),
+		escpos.CharSize(0x00), escpos.LineSpacing(48), []byte("Spaced\n"),
+		escpos.DefaultLineSpacing(), []byte("Default\n"),
+	)},
+	{"justify", stream(
+		escpos.Justify(escpos.JustifyLeft), []byte("Left\n"),
+		escpos.Justify(escpos.JustifyCenter), []byte("Center\n"),
+		escpos.Justify(escpos.JustifyRight), []byte("Right\n"),
+		escpos.CharSize(0x11), []byte("Large right\n"),
+	)},
+	{"upsidedown", stream(
+		[]byte("Upright\n"),
+		escpos.PrintMode(escpos.ModeUpsideDown), []byte("Upside down\n"),
+	)},
+	{"bitmap", stream(
+		escpos.Bitmap(48, 6, diagonal(48, 6)),
+		escpos.FeedRows(8),
+		escpos.Bitmap(16, 48, bytes.Repeat([]byte{0xaa}, 16*48)),
+	)},
+	{"barcodes", stream(
+		escpos.HRIPosition(escpos.HRIBelow), escpos.BarcodeHeight(40),
+		escpos.Barcode(2, "400638133393"), []byte{escpos.LF},
+		escpos.Justify(escpos.JustifyCenter),
+		escpos.Barcode(3, "9638507"), []byte{escpos.LF},
+		escpos.BarcodeWidth(2), escpos.HRIPosition(escpos.HRIBoth),
+		escpos.BarcodeWithLength(4, "CODE-39"),
+		escpos.Justify(escpos.JustifyLeft), escpos.HRIPosition(escpos.HRINone),
+		escpos.Barcode(0, "03600029145"), []byte{escpos.LF},
+	)},
+}
+
+// diagonal returns a w x h byte bitmap with a diagonal line.
+func diagonal(h, rowBytes int) []byte {
+	b := make([]byte, h*rowBytes)
+	for y := 0; y < h; y++ {
+		x := y * rowBytes * 8 / h
+		b[y*rowBytes+x/8] |= 0x80 >> uint(x%8)
+	}
+	return b
+}
+
+func TestGolden(t *testing.T) {
+	for _, tt := range goldenTests {
+		t.Run(tt.name, func(t *testing.T) {
+			img := renderClean(t, tt.data).Image()
+			path := filepath.Join("testdata", tt.name+".png")
+			if *update {
+				var buf bytes.Buffer
+				if err := png.Encode(&buf, img); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+			want, err := png.Decode(f)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if p, ok := diff(img, want); ok {
+				t.Errorf("image differs from %s at %v; run go test -update to accept it", path, p)
+			}
+		})
+	}
+}
+
+// TestSplitWrites checks that commands cut across writes are put back
+// together, as when Printer sends a bitmap a row at a time.
+func TestSplitWrites(t *testing.T) {
+	for _, tt := range goldenTests {
+		want := renderClean(t, tt.data).Image()
+		e := New()
+		for i := range tt.data {
+			e.Write(tt.data[i : i+1])
+		}
+		e.Close()
+		if d := e.Diagnostics(); len(d) != 0 {
+			t.Errorf("%s: diagnostics: %v", tt.name, d)
+		}
+		if p, ok := diff(e.Image(), want); ok {
+			t.Errorf("%s: written a byte at a time, image differs at %v", tt.name, p)
+		}
+	}
+}
+
+// diff returns the first point where a and b differ.
+func diff(a, b image.Image) (image.Point, bool) {
+	if a.Bounds() != b.Bounds() {
+		return b.Bounds().Max, true
+	}
+	r := a.Bounds()
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			if color.GrayModel.Convert(a.At(x, y)) != color.GrayModel.Convert(b.At(x, y)) {
+				return image.Pt(x, y), true
+			}
+		}
+	}
+	return image.Point{}, false
+}
+
+// dots returns the printed rows of e as strings of '#' and '.'.
+func dots(e *Emulator) []string {
+	img := e.Image()
+	r := img.Bounds()
+	lines := make([]string, r.Dy())
+	for y := range lines {
+		var b strings.Builder
+		for x := 0; x < r.Dx(); x++ {
+			if color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y == 0 {
+				b.WriteByte('#')
+			} else {
+				b.WriteByte('.')
+			}
+		}
+		lines[y] = b.String()
+	}
+	return lines
+}
+
+// inkX returns the leftmost and rightmost columns with a dot.
+func inkX(lines []string) (min, max int) {
+	min, max = Width, -1
+	for _, l := range lines {
+		if i := strings.IndexByte(l, '#'); i >= 0 && i < min {
+			min = i
+		}
+		if i := strings.LastIndexByte(l, '#'); i > max {
+			max = i
+		}
+	}
+	return min, max
+}
+
+func TestLineAdvance(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		rows int
+	}{
+		{"line", []byte("A\n"), 32},
+		{"blank line", []byte("\n"), 32},
+		{"unterminated", []byte("A"), 0},
+		{"FF", stream([]byte("A"), escpos.Flush()), 32},
+		{"FF on empty line", escpos.Flush(), 0},
+		{"CR", []byte("A\r\n"), 32},
+		{"line spacing", stream(escpos.LineSpacing(40), []byte("A\n")), 40},
+		{"spacing below char height", stream(escpos.LineSpacing(10), []byte("A\n")), 24},
+		{"default spacing", stream(escpos.LineSpacing(40), escpos.DefaultLineSpacing(), []byte("A\n")), 32},
+		{"reset", stream(escpos.LineSpacing(40), escpos.Reset(), []byte("A\n")), 32},
+		{"double height", stream(escpos.PrintMode(escpos.ModeDoubleHeight), []byte("A\n")), 48},
+		{"char size", stream(escpos.CharSize(0x01), []byte("A\n")), 48},
+		{"wrap", []byte(strings.Repeat("A", 33) + "\n"), 64},
+		{"wrap double width", stream(escpos.PrintMode(escpos.ModeDoubleWidth), []byte(strings.Repeat("A", 17)+"\n")), 64},
+		{"feed rows", escpos.FeedRows(7), 7},
+		{"feed rows ends line", stream([]byte("A"), escpos.FeedRows(7)), 39},
+		{"feed lines", escpos.FeedLines(2), 64},
+		{"bitmap", escpos.Bitmap(3, 1, []byte{0, 0, 0}), 3},
+		{"bitmap ends line", stream([]byte("A"), escpos.Bitmap(3, 1, []byte{0, 0, 0})), 35},
+		{"offline", stream(escpos.Online(false), []byte("A\n"), escpos.FeedRows(5)), 5},
+		{"online", stream(escpos.Online(false), escpos.Online(true), []byte("A\n")), 32},
+		{"heat and density ignored", stream(escpos.HeatConfig(20, 60, 250), escpos.Density(14, 4), escpos.Sleep(1), escpos.Wake()), 0},
+	}
+	for _, tt := range tests {
+		e := renderClean(t, tt.data)
+		if got := e.Image().Bounds().Dy(); got != tt.rows {
+			t.Errorf("%s: %d rows, want %d", tt.name, got, tt.rows)
+		}
+	}
+}
+
+func TestOfflineDropsPrinting(t *testing.T) {
+	e := renderClean(t, stream(
+		escpos.Online(false),
+		[]byte("A\n"),
+		escpos.Bitmap(1, 1, []byte{0xff}),
+		escpos.Barcode(4, "A"), []byte{escpos.LF},
+	))
+	if rows := e.Image().Bounds().Dy(); rows != 0 {
+		t.Errorf("offline printer printed %d rows", rows)
+	}
+}
+
+func TestJustify(t *testing.T) {
+	left, _ := inkX(dots(renderClean(t, []byte("A\n"))))
+	for _, tt := range []struct {
+		pos    byte
+		offset int
+	}{
+		{escpos.JustifyLeft, 0},
+		{escpos.JustifyCenter, (Width - CharWidth) / 2},
+		{escpos.JustifyRight, Width - CharWidth},
+		{'1', (Width - CharWidth) / 2}, // ASCII digits are accepted too
+	} {
+		got, _ := inkX(dots(renderClean(t, stream(escpos.Justify(tt.pos), []byte("A\n")))))
+		if got != left+tt.offset {
+			t.Errorf("ESC a %d: ink starts at %d, want %d", tt.pos, got, left+tt.offset)
+		}
+	}
+}
+
+func TestScaledText(t *testing.T) {
+	normal := dots(renderClean(t, []byte("AB\n")))
+	wide := dots(renderClean(t, stream(escpos.PrintMode(escpos.ModeDoubleWidth), []byte("AB\n"))))
+	tall := dots(renderClean(t, stream(escpos.PrintMode(escpos.ModeDoubleHeight), []byte("AB\n"))))
+	large := dots(renderClean(t, stream(escpos.CharSize(0x11), []byte("AB\n"))))
+	for y := 0; y < CharHeight; y++ {
+		for x := 0; x < 2*CharWidth; x++ {
+			n := normal[y][x]
+			if wide[y][2*x] != n || wide[y][2*x+1] != n {
+				t.Fatalf("double width differs at (%d, %d)", x, y)
+			}
+			if tall[2*y][x] != n || tall[2*y+1][x] != n {
+				t.Fatalf("double height differs at (%d, %d)", x, y)
+			}
+			if large[2*y][2*x] != n || large[2*y+1][2*x+1] != n {
+				t.Fatalf("GS ! 0x11 differs at (%d, %d)", x, y)
+			}
+		}
+	}
+}
+
+func TestTextModes(t *testing.T) {
+	normal := dots(renderClean(t, []byte("A\n")))
+	inverse := dots(renderClean(t, stream(escpos.PrintMode(escpos.ModeInverse), []byte("A\n"))))
+	upside := dots(renderClean(t, stream(escpos.PrintMode(escpos.ModeUpsideDown), []byte("A\n"))))
+	strike := dots(renderClean(t, stream(escpos.PrintMode(escpos.ModeStrike), []byte("A\n"))))
+	underline := dots(renderClean(t, stream(escpos.Underline(2), []byte("A\n"))))
+	for y := 0; y < CharHeight; y++ {
+		for x := 0; x < CharWidth; x++ {
+			n := normal[y][x] == '#'
+			if inv := inverse[y][x] == '#'; inv == n {
+				t.Fatalf("inverse not inverted at (%d, %d)", x, y)
+			}
+			if upside[CharHeight-1-y][Width-1-x] != normal[y][x] {
+				t.Fatalf("upside down not rotated at (%d, %d)", x, y)
+			}
+		}
+	}
+	for _, tt := range []struct {
+		name  string
+		lines []string
+		row   int
+	}{
+		{"strike", strike, CharHeight / 2},
+		{"underline", underline, CharHeight - 1},
+		{"underline", underline, CharHeight - 2},
+	} {
+		if want := strings.Repeat("#", CharWidth); tt.lines[tt.row][:CharWidth] != want {
+			t.Errorf("%s: row %d = %s, want a solid line", tt.name, tt.row, tt.lines[tt.row][:CharWidth])
+		}
+	}
+	if strings.Contains(underline[CharHeight-3][:CharWidth], strings.Repeat("#", CharWidth)) {
+		t.Error("underline 2 is more than 2 dots thick")
+	}
+}
+
+func TestBitmap(t *testing.T) {
+	lines := dots(renderClean(t, escpos.Bitmap(2, 2, []byte{0xf0, 0x0f, 0x80, 0x01})))
+	want := []string{
+		"####........####",
+		"#..............#",
+	}
+	if len(lines) != len(want) {
+		t.Fatalf("%d rows, want %d", len(lines), len(want))
+	}
+	for y, w := range want {
+		if lines[y] != w+strings.Repeat(".", Width-len(w)) {
+			t.Errorf("row %d = %s", y, lines[y][:32])
+		}
+	}
+}
+
+func TestBarcode(t *testing.T) {
+	modules, ok := barcodeModules(barcodeEAN13, "400638133393")
+	if !ok {
+		t.Fatal("EAN-13 not encoded")
+	}
+	for _, tt := range []struct {
+		name   string
+		data   []byte
+		width  int
+		height int
+		hri    int
+	}{
+		{"default", stream(escpos.BarcodeWithLength(2, "400638133393")), 3, 50, 0},
+		{"narrow", stream(escpos.BarcodeWidth(2), escpos.BarcodeHeight(20), escpos.BarcodeWithLength(2, "400638133393")), 2, 20, 0},
+		{"label below", stream(escpos.HRIPosition(escpos.HRIBelow), escpos.BarcodeWithLength(2, "400638133393")), 3, 50, 1},
+		{"labels", stream(escpos.HRIPosition(escpos.HRIBoth), escpos.BarcodeWithLength(2, "400638133393")), 3, 50, 2},
+	} {
+		lines := dots(renderClean(t, tt.data))
+		if want := tt.height + tt.hri*CharHeight; len(lines) != want {
+			t.Errorf("%s: %d rows, want %d", tt.name, len(lines), want)
+			continue
+		}
+		bars := lines[tt.hri/2*CharHeight]
+		var want strings.Builder
+		for _, m := range modules {
+			c := "."
+			if m == '1' {
+				c = "#"
+			}
+			want.WriteString(strings.Repeat(c, tt.width))
+		}
+		if got := strings.TrimRight(bars, "."); got != strings.TrimRight(want.String(), ".") {
+			t.Errorf("%s: bars = %s", tt.name, got)
+		}
+		if bars != lines[tt.hri/2*CharHeight+tt.height-1] {
+			t.Errorf("%s: bars are not full height", tt.name)
+		}
+	}
+}
+
+func TestStatusReplies(t *testing.T) {
+	queries := stream(
+		escpos.RealTimeStatus(1), escpos.RealTimeStatus(2), escpos.RealTimeStatus(3), escpos.RealTimeStatus(4),
+		escpos.TransmitStatus(1), escpos.PaperStatus(),
+	)
+	tests := []struct {
+		name                   string
+		present, nearEnd, open bool
+		offline                bool
+		want                   []byte
+	}{
+		{"ready", true, false, false, false, []byte{0x12, 0x12, 0x12, 0x12, 0x00, 0x00}},
+		{"near end", true, true, false, false, []byte{0x12, 0x12, 0x12, 0x1e, 0x03, 0x00}},
+		{"paper out", false, false, false, false, []byte{0x12, 0x32, 0x12, 0x72, 0x0c, 0x04}},
+		{"cover open", true, false, true, false, []byte{0x12, 0x16, 0x12, 0x12, 0x00, 0x00}},
+		{"offline", true, false, false, true, []byte{0x1a, 0x12, 0x12, 0x12, 0x00, 0x00}},
+	}
+	for _, tt := range tests {
+		e := New()
+		e.SetPaper(tt.present, tt.nearEnd)
+		e.SetCoverOpen(tt.open)
+		if tt.offline {
+			e.Write(escpos.Online(false))
+		}
+		e.Write(queries)
+		got, err := io.ReadAll(e)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(got, tt.want) {
+			t.Errorf("%s: replies % x, want % x", tt.name, got, tt.want)
+		}
+		if d := e.Diagnostics(); len(d) != 0 {
+			t.Errorf("%s: diagnostics: %v", tt.name, d)
+		}
+	}
+}
+
+func TestClosed(t *testing.T) {
+	e := render(t, nil)
+	if _, err := e.Write([]byte("A")); err != ErrClosed {
+		t.Errorf("Write after Close = %v, want ErrClosed", err)
+	}
+	if _, err := e.Read(make([]byte, 1)); err != ErrClosed {
+		t.Errorf("Read after Close = %v, want ErrClosed", err)
+	}
+	if err := e.Close(); err != ErrClosed {
+		t.Errorf("second Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestDiagnostics(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want []Diagnostic
+	}{
+		{"control byte", []byte("A\x07\n"), []Diagnostic{{1, "unknown control byte 0x07"}}},
+		{"ESC", []byte("AB\x1b\x01\n"), []Diagnostic{{2, "unknown command ESC 0x01"}}},
+		{"GS", []byte("\x1d\x01A\n"), []Diagnostic{{0, "unknown command GS 0x01"}}},
+		{"DC2", []byte("\x12\x01\n"), []Diagnostic{{0, "unknown command DC2 0x01"}}},
+		{"DLE", []byte("\x10\x05\n"), []Diagnostic{{0, "unknown command DLE 0x05"}}},
+		{"DLE EOT", escpos.RealTimeStatus(9), []Diagnostic{{0, "DLE EOT: invalid status type 9"}}},
+		{"justification", escpos.Justify(5), []Diagnostic{{0, "ESC a: invalid justification 5"}}},
+		{"underline", escpos.Underline(7), []Diagnostic{{0, "ESC -: invalid underline weight 7"}}},
+		{"barcode width", escpos.BarcodeWidth(9), []Diagnostic{{0, "GS w: invalid barcode width 9"}}},
+		{"bitmap too wide", escpos.Bitmap(1, 49, make([]byte, 49)), []Diagnostic{{0, "DC2 *: row of 49 bytes exceeds print width"}}},
+		{"barcode data", stream(escpos.Barcode(2, "12a"), []byte{escpos.LF}), []Diagnostic{{0, `GS k: cannot encode "12a" as type 2, drawing placeholder`}}},
+		{"barcode too wide", stream(escpos.Barcode(4, "ABCDEFGHIJ"), []byte{escpos.LF}), []Diagnostic{{0, "GS k: barcode is 576 dots wide, clipped to 384"}}},
+		{"truncated", []byte("A\n\x1b!"), []Diagnostic{{2, "truncated command 1b 21"}}},
+		{"truncated bitmap", escpos.Bitmap(2, 1, []byte{0xff}), []Diagnostic{{0, "truncated command 12 2a 02 01 ff"}}},
+		{"barcode type", []byte("A\n\x1dk"), []Diagnostic{{2, "GS k: missing barcode type"}}},
+		{"barcode end", escpos.Barcode(4, "AB"), []Diagnostic{{0, "GS k: barcode data not terminated"}}},
+	}
+	for _, tt := range tests {
+		got := render(t, tt.data).Diagnostics()
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: diagnostics %v, want %v", tt.name, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("%s: diagnostic %q, want %q", tt.name, got[i], tt.want[i])
+			}
+		}
+	}
+}
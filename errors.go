@@ -0,0 +1,51 @@
+package thermalprinter
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+)
+
+var (
+	// ErrClosed is returned when the printer or its transport has
+	// been closed.
+	ErrClosed = errors.New("thermalprinter: printer closed")
+
+	// ErrWriteTimeout is returned when the transport could not
+	// accept data in time.
+	ErrWriteTimeout = errors.New("thermalprinter: write timeout")
+
+	// ErrOffline is returned by print commands issued after Offline,
+	// which the printer would silently ignore.
+	ErrOffline = errors.New("thermalprinter: printer offline")
+
+	// ErrPaperOut is returned when the printer reports that it has
+	// run out of paper.
+	ErrPaperOut = errors.New("thermalprinter: paper out")
+)
+
+// transportError classifies an error returned by the transport so
+// that callers can test it with errors.Is.
+func transportError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if errors.Is(err, ErrClosed) || errors.Is(err, ErrWriteTimeout) {
+		return err
+	}
+	if errors.Is(err, os.ErrClosed) || errors.Is(err, net.ErrClosed) || errors.Is(err, io.ErrClosedPipe) {
+		return fmt.Errorf("%w: %v", ErrClosed, err)
+	}
+	if isTimeout(err) {
+		return fmt.Errorf("%w: %v", ErrWriteTimeout, err)
+	}
+	return err
+}
+
+// isTimeout reports whether err comes from an expired deadline.
+func isTimeout(err error) bool {
+	var te interface{ Timeout() bool }
+	return errors.Is(err, os.ErrDeadlineExceeded) || (errors.As(err, &te) && te.Timeout())
+}
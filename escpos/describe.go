@@ -0,0 +1,129 @@
+package escpos
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+var barcodeNames = []string{
+	"UPC-A", "UPC-E", "EAN13", "EAN8", "CODE39", "ITF",
+	"CODABAR", "CODE93", "CODE128", "CODE11", "MSI",
+}
+
+var printModeNames = []struct {
+	mask byte
+	name string
+}{
+	{ModeInverse, "inverse"},
+	{ModeUpsideDown, "upside-down"},
+	{ModeBold, "bold"},
+	{ModeDoubleHeight, "double-height"},
+	{ModeDoubleWidth, "double-width"},
+	{ModeStrike, "strike"},
+}
+
+// Describe returns the arguments of cmd in readable form, such as
+// "0x08 bold" for ESC ! 0x08 or "24 rows x 48 bytes" for DC2 *.
+func Describe(cmd Command) string {
+	arg := 0
+	if len(cmd.Params) > 0 {
+		arg = int(cmd.Params[0])
+	}
+	switch cmd.Op {
+	case OpText:
+		return fmt.Sprintf("%q", cmd.Data)
+	case OpUnknown:
+		return fmt.Sprintf("% x", cmd.Raw)
+	case OpWakeByte, OpWakeESC:
+		return "wake"
+	case OpPrintMode:
+		var names []string
+		for _, m := range printModeNames {
+			if byte(arg)&m.mask != 0 {
+				names = append(names, m.name)
+			}
+		}
+		if len(names) == 0 {
+			names = append(names, "normal")
+		}
+		return fmt.Sprintf("0x%02x %s", arg, strings.Join(names, " "))
+	case OpJustify:
+		return oneOf(arg, "left", "center", "right")
+	case OpLineSpacing:
+		return fmt.Sprintf("%d dots", arg)
+	case OpUnderline:
+		return oneOf(arg, "off", "1 dot", "2 dots")
+	case OpOnline:
+		if arg&1 != 0 {
+			return "online"
+		}
+		return "offline"
+	case OpSleep:
+		return fmt.Sprintf("after %d s", arg)
+	case OpFeedRows:
+		return fmt.Sprintf("%d dots", arg)
+	case OpFeedLines:
+		return fmt.Sprintf("%d lines", arg)
+	case OpHeatConfig:
+		return fmt.Sprintf("max dots %d, heat time %d us, interval %d us",
+			8*(arg+1), 10*int(cmd.Params[1]), 10*int(cmd.Params[2]))
+	case OpDensity:
+		return fmt.Sprintf("density %d%%, break time %d us", 50+5*(arg&0x1f), 250*(arg>>5))
+	case OpCharSize:
+		return fmt.Sprintf("width x%d height x%d", arg>>4+1, arg&0x0f+1)
+	case OpHRIPosition:
+		return "label " + oneOf(arg, "none", "above", "below", "above and below")
+	case OpBarcodeHeight:
+		return fmt.Sprintf("%d dots", arg)
+	case OpBarcodeWidth:
+		return fmt.Sprintf("module %d", arg)
+	case OpBarcode:
+		kind := arg
+		if kind >= 65 {
+			kind -= 65
+		}
+		name := fmt.Sprintf("type %d", kind)
+		if kind < len(barcodeNames) {
+			name = barcodeNames[kind]
+		}
+		return fmt.Sprintf("%s %q", name, cmd.Data)
+	case OpBitmap:
+		return fmt.Sprintf("%d rows x %d bytes", arg, cmd.Params[1])
+	case OpPaperStatus, OpTransmitStatus, OpRealTimeStatus:
+		return fmt.Sprintf("request %d", arg)
+	}
+	return ""
+}
+
+func oneOf(n int, names ...string) string {
+	if n >= '0' && n < '0'+len(names) {
+		n -= '0'
+	}
+	if n < 0 || n >= len(names) {
+		return fmt.Sprintf("invalid %d", n)
+	}
+	return names[n]
+}
+
+// Disassemble writes a listing of the stream read from r to w, one
+// command per line prefixed with its offset:
+//
+//	00000010  ESC !    0x08 bold
+//	00000013  text     "Bold text"
+//	00000022  LF
+func Disassemble(w io.Writer, r io.Reader) error {
+	s := NewScanner(r)
+	for s.Scan() {
+		if _, err := fmt.Fprintln(w, Listing(s.Command())); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// Listing formats cmd as one line of a Disassemble listing.
+func Listing(cmd Command) string {
+	line := fmt.Sprintf("%08x  %-7s  %s", cmd.Offset, cmd.Op, Describe(cmd))
+	return strings.TrimRight(line, " ")
+}
@@ -0,0 +1,95 @@
+package escpos
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+// listingStream has every Op, with arguments out of range and bytes
+// that are not commands.
+func listingStream() []byte {
+	return new(Builder).
+		Add(Wake(), WakeESC(), WakeESC(), Reset()).
+		Add(PrintMode(0), PrintMode(ModeBold|ModeDoubleWidth), PrintMode(0xff)).
+		Add(Justify(JustifyLeft), Justify('1'), Justify(JustifyRight), Justify(7)).
+		Add(LineSpacing(32), DefaultLineSpacing()).
+		Add(Underline(0), Underline(1), Underline('2'), Underline(3)).
+		Add(Online(false), Online(true), Sleep(30)).
+		Add(FeedRows(24), FeedLines(3)).
+		Add(HeatConfig(11, 120, 40), Density(10, 2)).
+		Add(CharSize(0x00), CharSize(0x11), CharSize(0x01)).
+		Add(HRIPosition(HRINone), HRIPosition(HRIBoth)).
+		Add(BarcodeHeight(80), BarcodeWidth(2)).
+		Add(Barcode(4, "AB-12"), []byte{LF}).
+		Add(BarcodeWithLength(8, "code 128")).
+		Add([]byte{GS, 'k', 20}, []byte("x"), []byte{0}).
+		Add(Bitmap(2, 3, []byte{1, 2, 3, 4, 5, 6})).
+		Add(PaperStatus(), TransmitStatus(1), RealTimeStatus(4)).
+		Println("Hello, \"world\"").
+		Add([]byte{CR, FF, 0x00, 0x07, 0x7f}).
+		Add([]byte{ESC, 'Z'}, []byte{GS, 'Z'}, []byte{DC2, 'Z'}, []byte{DLE, 'Z'}).
+		Add([]byte("caf\xe9\n")).
+		Bytes()
+}
+
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name)
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s; run go test -update to accept it:\n%s", path, got)
+	}
+}
+
+func TestDisassemble(t *testing.T) {
+	stream := listingStream()
+	var buf bytes.Buffer
+	if err := Disassemble(&buf, bytes.NewReader(stream)); err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "listing.golden", buf.Bytes())
+
+	cmds, err := Parse(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[Op]bool)
+	for _, cmd := range cmds {
+		seen[cmd.Op] = true
+	}
+	for op := range opNames {
+		if !seen[op] {
+			t.Errorf("the listing has no %v", op)
+		}
+	}
+}
+
+func TestDisassembleLoneESC(t *testing.T) {
+	// A lone ESC is a wake byte only when another ESC follows; at the
+	// end of the stream it is a truncated command.
+	var buf bytes.Buffer
+	err := Disassemble(&buf, bytes.NewReader([]byte{ESC, ESC, ESC}))
+	var se *SyntaxError
+	if !errors.As(err, &se) || !errors.Is(err, ErrTruncated) || se.Offset != 2 {
+		t.Errorf("Disassemble = %v, want ErrTruncated at offset 2", err)
+	}
+	want := "00000000  ESC      wake\n00000001  ESC      wake\n"
+	if buf.String() != want {
+		t.Errorf("listing = %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,193 @@
+package escpos
+
+// Print mode bits of ESC !.
+const (
+	ModeInverse      = 1 << 1
+	ModeUpsideDown   = 1 << 2
+	ModeBold         = 1 << 3
+	ModeDoubleHeight = 1 << 4
+	ModeDoubleWidth  = 1 << 5
+	ModeStrike       = 1 << 6
+)
+
+// Justification values of ESC a.
+const (
+	JustifyLeft = iota
+	JustifyCenter
+	JustifyRight
+)
+
+// Label positions of GS H.
+const (
+	HRINone = iota
+	HRIAbove
+	HRIBelow
+	HRIBoth
+)
+
+// Wake is sent to wake the printer from sleep. The printer needs a
+// moment before it accepts commands; WakeESC bytes are sent meanwhile.
+func Wake() []byte {
+	return []byte{0xff}
+}
+
+func WakeESC() []byte {
+	return []byte{ESC}
+}
+
+// Reset initializes the printer (ESC @).
+func Reset() []byte {
+	return []byte{ESC, '@'}
+}
+
+// Flush prints the buffered data (FF).
+func Flush() []byte {
+	return []byte{FF}
+}
+
+// PrintMode selects the character style (ESC !), a combination of the
+// Mode bits.
+func PrintMode(mode byte) []byte {
+	return []byte{ESC, '!', mode}
+}
+
+// Justify aligns the following lines (ESC a).
+func Justify(pos byte) []byte {
+	return []byte{ESC, 'a', pos}
+}
+
+// LineSpacing sets the line height in dots (ESC 3).
+func LineSpacing(dots byte) []byte {
+	return []byte{ESC, '3', dots}
+}
+
+// DefaultLineSpacing restores the default line height (ESC 2).
+func DefaultLineSpacing() []byte {
+	return []byte{ESC, '2'}
+}
+
+// Underline sets the underline weight: 0 off, 1 or 2 dots (ESC -).
+func Underline(weight byte) []byte {
+	return []byte{ESC, '-', weight}
+}
+
+// Online takes the printer online or offline (ESC =).
+func Online(online bool) []byte {
+	if online {
+		return []byte{ESC, '=', 1}
+	}
+	return []byte{ESC, '=', 0}
+}
+
+// Sleep puts the printer to sleep after the given seconds (ESC 8).
+func Sleep(seconds byte) []byte {
+	return []byte{ESC, '8', seconds}
+}
+
+// FeedRows prints the buffer and feeds the paper by dots (ESC J).
+func FeedRows(dots byte) []byte {
+	return []byte{ESC, 'J', dots}
+}
+
+// FeedLines prints the buffer and feeds the paper by lines (ESC d).
+func FeedLines(lines byte) []byte {
+	return []byte{ESC, 'd', lines}
+}
+
+// HeatConfig sets the max heating dots (units of 8 dots), the heating
+// time and the heating interval (units of 10us) (ESC 7).
+func HeatConfig(dots, time, interval byte) []byte {
+	return []byte{ESC, '7', dots, time, interval}
+}
+
+// Density sets the print density, 50% + 5% * density, and the break
+// time, breakTime * 250us (DC2 #).
+func Density(density, breakTime byte) []byte {
+	return []byte{DC2, '#', breakTime<<5 | density&0x1f}
+}
+
+// CharSize sets the character magnification (GS !): the high nibble
+// is the width and the low nibble the height multiplier minus one.
+func CharSize(size byte) []byte {
+	return []byte{GS, '!', size}
+}
+
+// HRIPosition sets where the barcode label is printed (GS H).
+func HRIPosition(pos byte) []byte {
+	return []byte{GS, 'H', pos}
+}
+
+// BarcodeHeight sets the barcode height in dots (GS h).
+func BarcodeHeight(dots byte) []byte {
+	return []byte{GS, 'h', dots}
+}
+
+// BarcodeWidth sets the barcode module width, 2 to 6 (GS w).
+func BarcodeWidth(width byte) []byte {
+	return []byte{GS, 'w', width}
+}
+
+// Barcode prints data as a barcode of the given type (GS k m d1...dk).
+// The data is not terminated; the printer ends it at the next control
+// byte, such as the line feed that normally follows.
+func Barcode(kind byte, data string) []byte {
+	return append([]byte{GS, 'k', kind}, data...)
+}
+
+// BarcodeWithLength prints data as a barcode using the length-prefixed
+// form of GS k understood by newer firmware (GS k m+65 n d1...dn).
+func BarcodeWithLength(kind byte, data string) []byte {
+	return append([]byte{GS, 'k', kind + 65, byte(len(data))}, data...)
+}
+
+// BitmapHeader starts a bitmap of rows rows of rowBytes bytes each
+// (DC2 *). The rows, most significant bit leftmost, must follow.
+func BitmapHeader(rows, rowBytes byte) []byte {
+	return []byte{DC2, '*', rows, rowBytes}
+}
+
+// Bitmap is BitmapHeader followed by the rows in data.
+func Bitmap(rows, rowBytes byte, data []byte) []byte {
+	return append(BitmapHeader(rows, rowBytes), data...)
+}
+
+// PaperStatus asks for the paper sensor state (ESC v).
+func PaperStatus() []byte {
+	return []byte{ESC, 'v', 0}
+}
+
+// TransmitStatus asks for the status of the given type (GS r).
+func TransmitStatus(n byte) []byte {
+	return []byte{GS, 'r', n}
+}
+
+// RealTimeStatus asks for the status of the given type (DLE EOT),
+// answered even while the printer is busy.
+func RealTimeStatus(n byte) []byte {
+	return []byte{DLE, 4, n}
+}
+
+// Builder assembles a job offline.
+type Builder struct {
+	buf []byte
+}
+
+// Add appends commands built by the functions of this package.
+func (b *Builder) Add(cmds ...[]byte) *Builder {
+	for _, cmd := range cmds {
+		b.buf = append(b.buf, cmd...)
+	}
+	return b
+}
+
+// Println appends text followed by a line feed.
+func (b *Builder) Println(text string) *Builder {
+	b.buf = append(b.buf, text...)
+	b.buf = append(b.buf, LF)
+	return b
+}
+
+// Bytes returns the job built so far.
+func (b *Builder) Bytes() []byte {
+	return b.buf
+}
@@ -0,0 +1,119 @@
+package escpos
+
+import (
+	"bytes"
+	"testing"
+)
+
+var encodeTests = []struct {
+	name   string
+	got    []byte
+	want   []byte
+	op     Op
+	params []byte
+	data   []byte
+}{
+	{"Wake", Wake(), []byte{0xff}, OpWakeByte, nil, nil},
+	{"Reset", Reset(), []byte{0x1b, 0x40}, OpReset, nil, nil},
+	{"Flush", Flush(), []byte{0x0c}, OpFF, nil, nil},
+	{"PrintMode", PrintMode(ModeBold | ModeDoubleWidth), []byte{0x1b, 0x21, 0x28}, OpPrintMode, []byte{0x28}, nil},
+	{"Justify", Justify(JustifyCenter), []byte{0x1b, 0x61, 0x01}, OpJustify, []byte{1}, nil},
+	{"LineSpacing", LineSpacing(32), []byte{0x1b, 0x33, 0x20}, OpLineSpacing, []byte{32}, nil},
+	{"DefaultLineSpacing", DefaultLineSpacing(), []byte{0x1b, 0x32}, OpDefaultLineSpacing, nil, nil},
+	{"Underline", Underline(2), []byte{0x1b, 0x2d, 0x02}, OpUnderline, []byte{2}, nil},
+	{"Online", Online(true), []byte{0x1b, 0x3d, 0x01}, OpOnline, []byte{1}, nil},
+	{"Offline", Online(false), []byte{0x1b, 0x3d, 0x00}, OpOnline, []byte{0}, nil},
+	{"Sleep", Sleep(1), []byte{0x1b, 0x38, 0x01}, OpSleep, []byte{1}, nil},
+	{"FeedRows", FeedRows(24), []byte{0x1b, 0x4a, 0x18}, OpFeedRows, []byte{24}, nil},
+	{"FeedLines", FeedLines(3), []byte{0x1b, 0x64, 0x03}, OpFeedLines, []byte{3}, nil},
+	{"HeatConfig", HeatConfig(20, 60, 250), []byte{0x1b, 0x37, 0x14, 0x3c, 0xfa}, OpHeatConfig, []byte{20, 60, 250}, nil},
+	{"Density", Density(14, 4), []byte{0x12, 0x23, 0x8e}, OpDensity, []byte{0x8e}, nil},
+	{"CharSize", CharSize(0x11), []byte{0x1d, 0x21, 0x11}, OpCharSize, []byte{0x11}, nil},
+	{"HRIPosition", HRIPosition(HRIBelow), []byte{0x1d, 0x48, 0x02}, OpHRIPosition, []byte{2}, nil},
+	{"BarcodeHeight", BarcodeHeight(50), []byte{0x1d, 0x68, 0x32}, OpBarcodeHeight, []byte{50}, nil},
+	{"BarcodeWidth", BarcodeWidth(3), []byte{0x1d, 0x77, 0x03}, OpBarcodeWidth, []byte{3}, nil},
+	{"Barcode", Barcode(4, "AB12"), []byte{0x1d, 0x6b, 0x04, 'A', 'B', '1', '2'}, OpBarcode, []byte{4}, []byte("AB12")},
+	{"BarcodeWithLength", BarcodeWithLength(4, "AB12"), []byte{0x1d, 0x6b, 0x45, 0x04, 'A', 'B', '1', '2'}, OpBarcode, []byte{0x45, 4}, []byte("AB12")},
+	{"Bitmap", Bitmap(2, 1, []byte{0xf0, 0x0f}), []byte{0x12, 0x2a, 0x02, 0x01, 0xf0, 0x0f}, OpBitmap, []byte{2, 1}, []byte{0xf0, 0x0f}},
+	{"PaperStatus", PaperStatus(), []byte{0x1b, 0x76, 0x00}, OpPaperStatus, []byte{0}, nil},
+	{"TransmitStatus", TransmitStatus(1), []byte{0x1d, 0x72, 0x01}, OpTransmitStatus, []byte{1}, nil},
+	{"RealTimeStatus", RealTimeStatus(2), []byte{0x10, 0x04, 0x02}, OpRealTimeStatus, []byte{2}, nil},
+}
+
+func TestEncode(t *testing.T) {
+	for _, tt := range encodeTests {
+		if !bytes.Equal(tt.got, tt.want) {
+			t.Errorf("%s = % x, want % x", tt.name, tt.got, tt.want)
+		}
+	}
+}
+
+func TestBitmapHeader(t *testing.T) {
+	// The header alone is incomplete; Bitmap covers the parse.
+	if got, want := BitmapHeader(2, 1), []byte{0x12, 0x2a, 0x02, 0x01}; !bytes.Equal(got, want) {
+		t.Errorf("BitmapHeader = % x, want % x", got, want)
+	}
+}
+
+func TestEncodeRoundTrip(t *testing.T) {
+	for _, tt := range encodeTests {
+		// Barcode data without a length is ended by the next control
+		// byte, as Printer does with a line feed.
+		stream := append(append([]byte(nil), tt.got...), LF)
+		cmds, err := Parse(stream)
+		if err != nil {
+			t.Errorf("%s: Parse: %v", tt.name, err)
+			continue
+		}
+		if len(cmds) != 2 || cmds[1].Op != OpLF {
+			t.Errorf("%s: Parse gave %d commands, want the command and LF", tt.name, len(cmds))
+			continue
+		}
+		cmd := cmds[0]
+		if cmd.Op != tt.op {
+			t.Errorf("%s: Op = %v, want %v", tt.name, cmd.Op, tt.op)
+		}
+		if !bytes.Equal(cmd.Raw, tt.got) {
+			t.Errorf("%s: Raw = % x, want % x", tt.name, cmd.Raw, tt.got)
+		}
+		if !bytes.Equal(cmd.Params, tt.params) {
+			t.Errorf("%s: Params = % x, want % x", tt.name, cmd.Params, tt.params)
+		}
+		if !bytes.Equal(cmd.Data, tt.data) {
+			t.Errorf("%s: Data = % x, want % x", tt.name, cmd.Data, tt.data)
+		}
+	}
+}
+
+func TestWakeESCRoundTrip(t *testing.T) {
+	// A lone ESC only parses as a wake byte when another ESC follows.
+	stream := new(Builder).Add(WakeESC(), WakeESC(), Reset()).Bytes()
+	if want := []byte{0x1b, 0x1b, 0x1b, 0x40}; !bytes.Equal(stream, want) {
+		t.Fatalf("stream = % x, want % x", stream, want)
+	}
+	cmds, err := Parse(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := []Op{OpWakeESC, OpWakeESC, OpReset}
+	if len(cmds) != len(ops) {
+		t.Fatalf("Parse gave %d commands, want %d", len(cmds), len(ops))
+	}
+	for i, op := range ops {
+		if cmds[i].Op != op {
+			t.Errorf("command %d: Op = %v, want %v", i, cmds[i].Op, op)
+		}
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	got := new(Builder).
+		Add(Reset(), Justify(JustifyCenter)).
+		Println("Hi").
+		Add(Justify(JustifyLeft)).
+		Bytes()
+	want := []byte{0x1b, 0x40, 0x1b, 0x61, 0x01, 'H', 'i', 0x0a, 0x1b, 0x61, 0x00}
+	if !bytes.Equal(got, want) {
+		t.Errorf("Bytes() = % x, want % x", got, want)
+	}
+}
@@ -0,0 +1,76 @@
+// Package escpos parses the ESC/POS command stream understood by the
+// CSN-A2 and similar receipt printers.
+package escpos
+
+// Op identifies a command.
+type Op int
+
+const (
+	OpUnknown            Op = iota
+	OpText                  // run of printable bytes
+	OpLF                    // LF: print and feed one line
+	OpCR                    // CR: ignored by the printer
+	OpFF                    // FF: print buffer
+	OpWakeByte              // 0xFF sent to wake the printer
+	OpWakeESC               // lone ESC sent while waking the printer
+	OpReset                 // ESC @
+	OpDefaultLineSpacing    // ESC 2
+	OpPrintMode             // ESC ! n
+	OpJustify               // ESC a n
+	OpLineSpacing           // ESC 3 n
+	OpUnderline             // ESC - n
+	OpOnline                // ESC = n
+	OpSleep                 // ESC 8 n
+	OpFeedRows              // ESC J n
+	OpFeedLines             // ESC d n
+	OpHeatConfig            // ESC 7 n1 n2 n3
+	OpPaperStatus           // ESC v n
+	OpCharSize              // GS ! n
+	OpHRIPosition           // GS H n
+	OpBarcodeHeight         // GS h n
+	OpBarcodeWidth          // GS w n
+	OpBarcode               // GS k m data
+	OpTransmitStatus        // GS r n
+	OpDensity               // DC2 # n
+	OpBitmap                // DC2 * r n data
+	OpRealTimeStatus        // DLE EOT n
+)
+
+var opNames = map[Op]string{
+	OpUnknown:            "unknown",
+	OpText:               "text",
+	OpLF:                 "LF",
+	OpCR:                 "CR",
+	OpFF:                 "FF",
+	OpWakeByte:           "0xFF",
+	OpWakeESC:            "ESC",
+	OpReset:              "ESC @",
+	OpDefaultLineSpacing: "ESC 2",
+	OpPrintMode:          "ESC !",
+	OpJustify:            "ESC a",
+	OpLineSpacing:        "ESC 3",
+	OpUnderline:          "ESC -",
+	OpOnline:             "ESC =",
+	OpSleep:              "ESC 8",
+	OpFeedRows:           "ESC J",
+	OpFeedLines:          "ESC d",
+	OpHeatConfig:         "ESC 7",
+	OpPaperStatus:        "ESC v",
+	OpCharSize:           "GS !",
+	OpHRIPosition:        "GS H",
+	OpBarcodeHeight:      "GS h",
+	OpBarcodeWidth:       "GS w",
+	OpBarcode:            "GS k",
+	OpTransmitStatus:     "GS r",
+	OpDensity:            "DC2 #",
+	OpBitmap:             "DC2 *",
+	OpRealTimeStatus:     "DLE EOT",
+}
+
+// String returns the mnemonic of op, such as "ESC !".
+func (op Op) String() string {
+	if name, ok := opNames[op]; ok {
+		return name
+	}
+	return "unknown"
+}
@@ -0,0 +1,273 @@
+package escpos
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Control bytes that start commands.
+const (
+	LF  = 10
+	FF  = 12
+	CR  = 13
+	DLE = 16
+	DC2 = 18
+	ESC = 27
+	GS  = 29
+)
+
+// Largest command: DC2 * with 255 rows of 255 bytes.
+const maxCommandSize = 4 + 255*255
+
+// Longer runs of text are split into several commands.
+const maxTextRun = 1024
+
+// ErrTruncated is returned when the stream ends inside a command.
+var ErrTruncated = errors.New("escpos: truncated command")
+
+// Command is one command, or one run of text, of an ESC/POS stream.
+type Command struct {
+	Op     Op
+	Offset int    // position of the first byte in the stream
+	Raw    []byte // all bytes of the command
+	Params []byte // parameter bytes following the command code
+	Data   []byte // text, barcode data or bitmap rows
+}
+
+type commandDef struct {
+	op     Op
+	params int
+}
+
+var escCommands = map[byte]commandDef{
+	'@': {OpReset, 0},
+	'2': {OpDefaultLineSpacing, 0},
+	'!': {OpPrintMode, 1},
+	'a': {OpJustify, 1},
+	'3': {OpLineSpacing, 1},
+	'-': {OpUnderline, 1},
+	'=': {OpOnline, 1},
+	'8': {OpSleep, 1},
+	'J': {OpFeedRows, 1},
+	'd': {OpFeedLines, 1},
+	'7': {OpHeatConfig, 3},
+	'v': {OpPaperStatus, 1},
+}
+
+var gsCommands = map[byte]commandDef{
+	'!': {OpCharSize, 1},
+	'H': {OpHRIPosition, 1},
+	'h': {OpBarcodeHeight, 1},
+	'w': {OpBarcodeWidth, 1},
+	'r': {OpTransmitStatus, 1},
+}
+
+var dc2Commands = map[byte]commandDef{
+	'#': {OpDensity, 1},
+}
+
+// isText reports whether b is printed as a character.
+func isText(b byte) bool {
+	return b >= 0x20 && b != 0x7f && b != 0xff
+}
+
+// next decodes the command at the start of data. It returns n == 0
+// when data ends inside the command and more input may follow.
+func next(data []byte, atEOF bool) (cmd Command, n int, err error) {
+	defer func() {
+		if n > 0 {
+			cmd.Raw = data[:n]
+		} else if atEOF && len(data) > 0 {
+			err = ErrTruncated
+		}
+	}()
+
+	fixed := func(op Op, prefix, params int) (Command, int, error) {
+		n := prefix + params
+		if len(data) < n {
+			return Command{}, 0, nil
+		}
+		return Command{Op: op, Params: data[prefix:n]}, n, nil
+	}
+	lookup := func(table map[byte]commandDef) (Command, int, error) {
+		if len(data) < 2 {
+			return Command{}, 0, nil
+		}
+		def, ok := table[data[1]]
+		if !ok {
+			return Command{Op: OpUnknown}, 2, nil
+		}
+		return fixed(def.op, 2, def.params)
+	}
+
+	switch c := data[0]; {
+	case c == LF:
+		return Command{Op: OpLF}, 1, nil
+	case c == CR:
+		return Command{Op: OpCR}, 1, nil
+	case c == FF:
+		return Command{Op: OpFF}, 1, nil
+	case c == 0xff:
+		return Command{Op: OpWakeByte}, 1, nil
+	case c == ESC:
+		if len(data) >= 2 && data[1] == ESC {
+			return Command{Op: OpWakeESC}, 1, nil
+		}
+		return lookup(escCommands)
+	case c == GS:
+		if len(data) >= 2 && data[1] == 'k' {
+			return barcode(data, atEOF)
+		}
+		return lookup(gsCommands)
+	case c == DC2:
+		if len(data) >= 2 && data[1] == '*' {
+			return bitmap(data)
+		}
+		return lookup(dc2Commands)
+	case c == DLE:
+		if len(data) < 2 {
+			return Command{}, 0, nil
+		}
+		if data[1] != 4 {
+			return Command{Op: OpUnknown}, 2, nil
+		}
+		return fixed(OpRealTimeStatus, 2, 1)
+	case isText(c):
+		n := 1
+		for n < len(data) && n < maxTextRun && isText(data[n]) {
+			n++
+		}
+		if n == len(data) && n < maxTextRun && !atEOF {
+			// The run may continue in the next read.
+			return Command{}, 0, nil
+		}
+		return Command{Op: OpText, Data: data[:n]}, n, nil
+	default:
+		return Command{Op: OpUnknown}, 1, nil
+	}
+}
+
+// barcode decodes GS k m d1...dk NUL (m < 65) or GS k m n d1...dn.
+// The first form also ends at any control byte, which is not part
+// of the command; thermalprinter follows the data with a line feed.
+func barcode(data []byte, atEOF bool) (Command, int, error) {
+	if len(data) < 3 {
+		return Command{}, 0, nil
+	}
+	if data[2] >= 65 {
+		if len(data) < 4 {
+			return Command{}, 0, nil
+		}
+		n := 4 + int(data[3])
+		if len(data) < n {
+			return Command{}, 0, nil
+		}
+		return Command{Op: OpBarcode, Params: data[2:4], Data: data[4:n]}, n, nil
+	}
+	for i := 3; i < len(data); i++ {
+		if data[i] == 0 {
+			return Command{Op: OpBarcode, Params: data[2:3], Data: data[3:i]}, i + 1, nil
+		}
+		if data[i] < 0x20 {
+			return Command{Op: OpBarcode, Params: data[2:3], Data: data[3:i]}, i, nil
+		}
+	}
+	if atEOF {
+		return Command{Op: OpBarcode, Params: data[2:3], Data: data[3:]}, len(data), nil
+	}
+	return Command{}, 0, nil
+}
+
+// bitmap decodes DC2 * r n followed by r rows of n bytes.
+func bitmap(data []byte) (Command, int, error) {
+	if len(data) < 4 {
+		return Command{}, 0, nil
+	}
+	n := 4 + int(data[2])*int(data[3])
+	if len(data) < n {
+		return Command{}, 0, nil
+	}
+	return Command{Op: OpBitmap, Params: data[2:4], Data: data[4:n]}, n, nil
+}
+
+// Parse splits a complete stream into commands.
+func Parse(data []byte) ([]Command, error) {
+	var cmds []Command
+	offset := 0
+	for offset < len(data) {
+		cmd, n, err := next(data[offset:], true)
+		if err != nil {
+			return cmds, &SyntaxError{Offset: offset, Err: err}
+		}
+		cmd.Offset = offset
+		cmds = append(cmds, cmd)
+		offset += n
+	}
+	return cmds, nil
+}
+
+// SyntaxError records where in the stream parsing failed.
+type SyntaxError struct {
+	Offset int
+	Err    error
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("offset %d: %v", e.Offset, e.Err)
+}
+
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
+// Scanner reads commands from a stream one at a time.
+type Scanner struct {
+	s      *bufio.Scanner
+	cmd    Command
+	offset int
+}
+
+func NewScanner(r io.Reader) *Scanner {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 4096), maxCommandSize+1)
+	sc := &Scanner{s: s}
+	s.Split(sc.split)
+	return sc
+}
+
+func (sc *Scanner) split(data []byte, atEOF bool) (int, []byte, error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	cmd, n, err := next(data, atEOF)
+	if err != nil {
+		return 0, nil, &SyntaxError{Offset: sc.offset, Err: err}
+	}
+	if n == 0 {
+		return 0, nil, nil
+	}
+	cmd.Offset = sc.offset
+	sc.cmd = cmd
+	sc.offset += n
+	return n, data[:n], nil
+}
+
+// Scan advances to the next command, which is then available through
+// Command. It returns false at the end of the stream or on error.
+func (sc *Scanner) Scan() bool {
+	return sc.s.Scan()
+}
+
+// Command returns the most recent command read by Scan. Its slices
+// are only valid until the next call to Scan.
+func (sc *Scanner) Command() Command {
+	return sc.cmd
+}
+
+// Err returns the first error met by the Scanner, such as a
+// *SyntaxError wrapping ErrTruncated.
+func (sc *Scanner) Err() error {
+	return sc.s.Err()
+}
@@ -0,0 +1,150 @@
+package escpos
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+// scanStream is a stream with every kind of command, including the
+// ones whose length depends on their parameters.
+func scanStream() []byte {
+	return new(Builder).
+		Add(Wake(), WakeESC(), WakeESC(), Reset(), PrintMode(ModeBold)).
+		Println("Hello, world").
+		Add(Barcode(4, "AB12"), []byte{LF}).
+		Add(BarcodeWithLength(4, "CD34")).
+		Add([]byte{GS, 'k', 2}, []byte("012345678905"), []byte{0}).
+		Add(Bitmap(3, 2, []byte{1, 2, 3, 4, 5, 6})).
+		Add([]byte{CR, 0x07, ESC, 'x', DLE, 'y'}, RealTimeStatus(1)).
+		Add([]byte(strings.Repeat("z", maxTextRun+10)), Flush()).
+		Bytes()
+}
+
+func scanAll(r io.Reader) ([]Command, error) {
+	var cmds []Command
+	s := NewScanner(r)
+	for s.Scan() {
+		cmd := s.Command()
+		// The slices are reused by the next Scan.
+		cmd.Raw = append([]byte(nil), cmd.Raw...)
+		cmds = append(cmds, cmd)
+	}
+	return cmds, s.Err()
+}
+
+func sameCommands(t *testing.T, what string, got, want []Command) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%s: %d commands, want %d", what, len(got), len(want))
+	}
+	for i := range got {
+		if got[i].Op != want[i].Op || got[i].Offset != want[i].Offset || !bytes.Equal(got[i].Raw, want[i].Raw) {
+			t.Fatalf("%s: command %d = %v at %d % x, want %v at %d % x", what, i,
+				got[i].Op, got[i].Offset, got[i].Raw, want[i].Op, want[i].Offset, want[i].Raw)
+		}
+	}
+}
+
+func TestScanner(t *testing.T) {
+	stream := scanStream()
+	want, err := Parse(stream)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ops := []Op{
+		OpWakeByte, OpWakeESC, OpWakeESC, OpReset, OpPrintMode, OpText, OpLF,
+		OpBarcode, OpLF, OpBarcode, OpBarcode, OpBitmap,
+		OpCR, OpUnknown, OpUnknown, OpUnknown, OpRealTimeStatus,
+		OpText, OpText, OpFF,
+	}
+	if len(want) != len(ops) {
+		t.Fatalf("Parse gave %d commands, want %d", len(want), len(ops))
+	}
+	for i, op := range ops {
+		if want[i].Op != op {
+			t.Errorf("command %d: Op = %v, want %v", i, want[i].Op, op)
+		}
+	}
+	if n := len(want[17].Raw); n != maxTextRun {
+		t.Errorf("long text split after %d bytes, want %d", n, maxTextRun)
+	}
+
+	got, err := scanAll(bytes.NewReader(stream))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameCommands(t, "one read", got, want)
+
+	got, err = scanAll(iotest.OneByteReader(bytes.NewReader(stream)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sameCommands(t, "one byte reads", got, want)
+
+	for i := 1; i < len(stream); i++ {
+		r := io.MultiReader(bytes.NewReader(stream[:i]), bytes.NewReader(stream[i:]))
+		got, err := scanAll(r)
+		if err != nil {
+			t.Fatalf("split at %d: %v", i, err)
+		}
+		sameCommands(t, "split reads", got, want)
+	}
+}
+
+func TestScannerLargestBitmap(t *testing.T) {
+	stream := Bitmap(255, 255, make([]byte, 255*255))
+	got, err := scanAll(iotest.HalfReader(bytes.NewReader(stream)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 1 || got[0].Op != OpBitmap || len(got[0].Raw) != maxCommandSize {
+		t.Errorf("scanned %d commands, want one bitmap of %d bytes", len(got), maxCommandSize)
+	}
+}
+
+func TestScannerTruncated(t *testing.T) {
+	tests := []struct {
+		stream []byte
+		cmds   int
+		offset int
+	}{
+		{[]byte("ab\n\x1b!"), 2, 3},
+		{[]byte("\x1b"), 0, 0},
+		{[]byte("\x1d"), 0, 0},
+		{[]byte("\x10"), 0, 0},
+		{[]byte("\x10\x04"), 0, 0},
+		{[]byte("\x1b7\x14\x3c"), 0, 0},
+		{[]byte("\x1dkE\x04AB"), 0, 0},
+		{[]byte("\x12*\x02\x01\xff"), 0, 0},
+	}
+	for _, tt := range tests {
+		for _, r := range []io.Reader{bytes.NewReader(tt.stream), iotest.OneByteReader(bytes.NewReader(tt.stream))} {
+			cmds, err := scanAll(r)
+			var se *SyntaxError
+			if !errors.As(err, &se) || !errors.Is(err, ErrTruncated) || se.Offset != tt.offset {
+				t.Errorf("% x: Err = %v, want ErrTruncated at offset %d", tt.stream, err, tt.offset)
+			}
+			if len(cmds) != tt.cmds {
+				t.Errorf("% x: %d commands before the error, want %d", tt.stream, len(cmds), tt.cmds)
+			}
+		}
+		if _, err := Parse(tt.stream); !errors.Is(err, ErrTruncated) {
+			t.Errorf("% x: Parse = %v, want ErrTruncated", tt.stream, err)
+		}
+	}
+}
+
+func TestScannerUnterminatedBarcode(t *testing.T) {
+	// Barcode data without a length ends with the stream.
+	cmds, err := scanAll(bytes.NewReader(Barcode(4, "AB")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cmds) != 1 || cmds[0].Op != OpBarcode {
+		t.Errorf("scanned %v, want one barcode", cmds)
+	}
+}
@@ -92,12 +92,13 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer printer.Close()
 	printer.Println("hello world!")
 	printer.Println("secondline.")
 	printer.Print("3")
 	printer.Print("4")
 	printer.Print("\n")
-	printer.PrintBitmap(adalogoWidth, adalogoHeight, adalogoData, false)
+	printer.PrintBitmap(adalogoWidth, adalogoHeight, adalogoData)
 	printer.Print("\n")
 	printer.Println("[end]")
 }
@@ -10,6 +10,7 @@ func main() {
 	if err != nil {
 		log.Fatal(err)
 	}
+	defer printer.Close()
 
 	// Test inverse on & off
 	printer.InverseOn()
@@ -0,0 +1,186 @@
+package thermalprinter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// Software flow control bytes sent by the printer.
+const (
+	xon  = 0x11 // DC1
+	xoff = 0x13 // DC3
+)
+
+// ErrFlowControlUnsupported is returned when flow control is requested
+// on a transport that cannot be read from.
+var ErrFlowControlUnsupported = errors.New("thermalprinter: flow control needs a readable transport")
+
+// WithFlowControl enables XON/XOFF flow control. A goroutine reads
+// the transport and pauses output while the printer has sent XOFF.
+// Output is then sent as fast as the printer accepts it instead of
+// being paced by the estimated print times. The printer must be
+// configured for XON/XOFF.
+func WithFlowControl() Option {
+	return func(p *Printer) {
+		p.flowControl = true
+	}
+}
+
+// flow tracks the XON/XOFF state reported by the printer. Other bytes
+// read from the transport are queued as status replies.
+type flow struct {
+	mu      sync.Mutex
+	paused  bool
+	resumed chan struct{} // closed when the printer sends XON
+	err     error         // read error that stopped the reader
+	clock   Clock
+
+	replies chan byte
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newFlow(r io.Reader, clock Clock) *flow {
+	f := &flow{
+		clock:   clock,
+		resumed: make(chan struct{}),
+		replies: make(chan byte, 64),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	go f.run(r)
+	return f
+}
+
+func (f *flow) run(r io.Reader) {
+	defer close(f.done)
+	buf := make([]byte, 64)
+	for {
+		select {
+		case <-f.stop:
+			return
+		default:
+		}
+		n, err := r.Read(buf)
+		for _, b := range buf[:n] {
+			switch b {
+			case xoff:
+				f.setPaused(true)
+			case xon:
+				f.setPaused(false)
+			default:
+				select {
+				case f.replies <- b:
+				default:
+					// Nobody is asking for status; drop it.
+				}
+			}
+		}
+		switch {
+		case err == nil:
+		case err == io.EOF || isTimeout(err):
+			if n == 0 {
+				// Nothing buffered; serial reads return this after
+				// their own read timeout.
+				select {
+				case <-f.clock.After(10 * time.Millisecond):
+				case <-f.stop:
+					return
+				}
+			}
+		default:
+			f.fail(transportError(err))
+			return
+		}
+	}
+}
+
+func (f *flow) setPaused(paused bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.paused == paused {
+		return
+	}
+	f.paused = paused
+	if paused {
+		f.resumed = make(chan struct{})
+	} else {
+		close(f.resumed)
+	}
+}
+
+func (f *flow) fail(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.err = err
+	if f.paused {
+		f.paused = false
+		close(f.resumed)
+	}
+}
+
+// wait blocks while the printer is paused, for at most until
+// deadline when it is not zero.
+func (f *flow) wait(ctx context.Context, deadline time.Time) error {
+	f.mu.Lock()
+	paused, resumed := f.paused, f.resumed
+	f.mu.Unlock()
+	if paused {
+		var expired <-chan time.Time
+		if !deadline.IsZero() {
+			expired = f.clock.After(deadline.Sub(f.clock.Now()))
+		}
+		select {
+		case <-resumed:
+		case <-expired:
+			return ErrWriteTimeout
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.err
+}
+
+// read returns the next status reply, or errNoReply at deadline.
+func (f *flow) read(deadline time.Time) (byte, error) {
+	expired := f.clock.After(deadline.Sub(f.clock.Now()))
+	select {
+	case b := <-f.replies:
+		return b, nil
+	case <-f.done:
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if f.err != nil {
+			return 0, f.err
+		}
+		return 0, ErrClosed
+	case <-expired:
+		return 0, errNoReply
+	}
+}
+
+// discard drops the replies queued so far.
+func (f *flow) discard() {
+	for {
+		select {
+		case <-f.replies:
+		default:
+			return
+		}
+	}
+}
+
+func (f *flow) close() {
+	close(f.stop)
+}
+
+func (p *Printer) stopFlow() {
+	if p.flow != nil {
+		p.flow.close()
+	}
+}
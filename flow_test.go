@@ -0,0 +1,236 @@
+package thermalprinter
+
+import (
+	"errors"
+	"github.com/koyachi/go-thermalprinter/emulator"
+	"io"
+	"sync"
+	"testing"
+	"time"
+)
+
+// lineTransport is an emulator on a line the test controls. Replies
+// to status queries are passed on; flow control bytes and read errors
+// are sent by the test.
+type lineTransport struct {
+	*emulator.Emulator
+	in   chan []byte
+	errs chan error
+	done chan struct{}
+
+	mu     sync.Mutex
+	writes int
+}
+
+func newLineTransport() *lineTransport {
+	return &lineTransport{
+		Emulator: emulator.New(),
+		in:       make(chan []byte, 64),
+		errs:     make(chan error, 1),
+		done:     make(chan struct{}),
+	}
+}
+
+func (l *lineTransport) Write(data []byte) (int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.writes++
+	n, err := l.Emulator.Write(data)
+	if reply, _ := io.ReadAll(l.Emulator); len(reply) > 0 {
+		l.in <- reply
+	}
+	return n, err
+}
+
+func (l *lineTransport) Read(data []byte) (int, error) {
+	select {
+	case b := <-l.in:
+		return copy(data, b), nil
+	case err := <-l.errs:
+		return 0, err
+	case <-l.done:
+		return 0, io.EOF
+	}
+}
+
+func (l *lineTransport) Close() error {
+	close(l.done)
+	return l.Emulator.Close()
+}
+
+func (l *lineTransport) writeCount() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.writes
+}
+
+func newLinePrinter(t *testing.T, clock Clock) (*Printer, *lineTransport) {
+	t.Helper()
+	l := newLineTransport()
+	p, err := NewPrinterWithTransport(l, 19200, 0, WithClock(clock), WithFlowControl())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { p.Close() })
+	return p, l
+}
+
+func testClock() *fakeClock {
+	return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+// waitFor polls cond until it holds, failing the test after a second.
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	for start := time.Now(); !cond(); time.Sleep(time.Millisecond) {
+		if time.Since(start) > time.Second {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func (f *flow) isPaused() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.paused
+}
+
+func TestFlowPausesWrites(t *testing.T) {
+	p, l := newLinePrinter(t, testClock())
+	setup := l.writeCount()
+	l.in <- []byte{xoff}
+	waitFor(t, "XOFF", p.flow.isPaused)
+
+	done := make(chan error, 1)
+	go func() { done <- p.Println("hello") }()
+	select {
+	case err := <-done:
+		t.Fatalf("Println returned %v during XOFF", err)
+	case <-time.After(50 * time.Millisecond):
+	}
+	if n := l.writeCount() - setup; n != 0 {
+		t.Fatalf("%d writes during XOFF", n)
+	}
+
+	l.in <- []byte{xon}
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if p.flow.isPaused() {
+		t.Error("still paused after XON")
+	}
+	if n := l.writeCount() - setup; n == 0 {
+		t.Error("nothing written after XON")
+	}
+	if d := l.Diagnostics(); len(d) != 0 {
+		t.Errorf("emulator diagnostics: %v", d)
+	}
+}
+
+func TestFlowStatusReplies(t *testing.T) {
+	// Replies are waited for on the system clock; a fake clock would
+	// expire the wait before the reader goroutine passes them on.
+	p, l := newLinePrinter(t, realClock{})
+	l.SetPaper(true, true)
+	// Flow control bytes around a stale reply, which is dropped before
+	// the queries are sent.
+	l.in <- []byte{xoff, 0x55, xon}
+	waitFor(t, "the stale reply", func() bool { return len(p.flow.replies) == 1 })
+	if p.flow.isPaused() {
+		t.Fatal("still paused after XON")
+	}
+
+	st, err := p.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Status{PaperPresent: true, PaperNearEnd: true, Online: true, Complete: true}); st != want {
+		t.Errorf("Status = %+v, want %+v", st, want)
+	}
+}
+
+func TestFlowDropsUnreadReplies(t *testing.T) {
+	p, l := newLinePrinter(t, testClock())
+	for i := 0; i < 2*cap(p.flow.replies); i++ {
+		l.in <- []byte{'x'}
+	}
+	// A full reply queue must not stop flow control bytes being seen.
+	l.in <- []byte{xoff}
+	waitFor(t, "XOFF", p.flow.isPaused)
+	if n := len(p.flow.replies); n != cap(p.flow.replies) {
+		t.Errorf("%d replies queued, want %d", n, cap(p.flow.replies))
+	}
+
+	p.flow.discard()
+	if _, err := p.flow.read(p.clock.Now().Add(time.Second)); err != errNoReply {
+		t.Errorf("read after discard = %v, want errNoReply", err)
+	}
+}
+
+func TestFlowReadError(t *testing.T) {
+	p, l := newLinePrinter(t, testClock())
+	setup := l.writeCount()
+	l.in <- []byte{xoff}
+	waitFor(t, "XOFF", p.flow.isPaused)
+	done := make(chan error, 1)
+	go func() { done <- p.Println("hello") }()
+
+	// The write waiting for XON gets the error instead.
+	lineDown := errors.New("line down")
+	l.errs <- lineDown
+	if err := <-done; !errors.Is(err, lineDown) {
+		t.Errorf("Println = %v, want %v", err, lineDown)
+	}
+	<-p.flow.done
+	if err := p.Println("again"); !errors.Is(err, lineDown) {
+		t.Errorf("Println after the reader stopped = %v, want %v", err, lineDown)
+	}
+	// On the fake clock the deadline would be as ready as the error.
+	p.flow.clock = stillClock{}
+	if _, err := p.flow.read(time.Time{}); !errors.Is(err, lineDown) {
+		t.Errorf("read after the reader stopped = %v, want %v", err, lineDown)
+	}
+	if n := l.writeCount() - setup; n != 0 {
+		t.Errorf("%d writes after the reader stopped", n)
+	}
+}
+
+// afterClock is a fakeClock that reports each wait on after.
+type afterClock struct {
+	fakeClock
+	after chan time.Duration
+}
+
+func (c *afterClock) After(d time.Duration) <-chan time.Time {
+	c.after <- d
+	return make(chan time.Time)
+}
+
+// stillClock is a clock on which time never passes.
+type stillClock struct{}
+
+func (stillClock) Now() time.Time                       { return time.Time{} }
+func (stillClock) After(time.Duration) <-chan time.Time { return nil }
+
+type eofReader struct{}
+
+func (eofReader) Read([]byte) (int, error) { return 0, io.EOF }
+
+func TestFlowReaderWaitsOnClock(t *testing.T) {
+	clock := &afterClock{after: make(chan time.Duration)}
+	f := newFlow(eofReader{}, clock)
+	select {
+	case d := <-clock.after:
+		if d != 10*time.Millisecond {
+			t.Errorf("reader waits %v after an empty read, want 10ms", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("reader does not wait on the clock after an empty read")
+	}
+	f.close()
+	select {
+	case <-f.done:
+	case <-time.After(time.Second):
+		t.Fatal("reader did not stop")
+	}
+}
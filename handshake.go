@@ -0,0 +1,77 @@
+package thermalprinter
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+const busyPollInterval = 2 * time.Millisecond
+
+// BusyLine reports the state of the printer's DTR (busy) output,
+// which is high while the printer cannot take more data.
+type BusyLine interface {
+	Busy() (bool, error)
+}
+
+// BusyLineFunc adapts a function to BusyLine.
+type BusyLineFunc func() (bool, error)
+
+func (f BusyLineFunc) Busy() (bool, error) {
+	return f()
+}
+
+// WithBusyLine paces output by the printer's busy line instead of the
+// estimated print times. Whenever l cannot be read the timing model
+// is used for that write.
+func WithBusyLine(l BusyLine) Option {
+	return func(p *Printer) {
+		p.busyLine = l
+	}
+}
+
+// GPIOLine reads a busy line wired to a GPIO exported through sysfs,
+// for example /sys/class/gpio/gpio18/value on a Raspberry Pi.
+type GPIOLine struct {
+	Path string
+}
+
+func (g GPIOLine) Busy() (bool, error) {
+	v, err := os.ReadFile(g.Path)
+	if err != nil {
+		return false, err
+	}
+	switch string(bytes.TrimSpace(v)) {
+	case "0":
+		return false, nil
+	case "1":
+		return true, nil
+	}
+	return false, fmt.Errorf("thermalprinter: unexpected GPIO value %q in %s", v, g.Path)
+}
+
+// waitNotBusy blocks while the busy line is high. It records whether
+// the line could be read so that send knows which pacing applies.
+func (p *Printer) waitNotBusy(ctx context.Context, deadline time.Time) error {
+	for {
+		busy, err := p.busyLine.Busy()
+		if err != nil {
+			p.handshaking = false
+			return nil
+		}
+		p.handshaking = true
+		if !busy {
+			return nil
+		}
+		if !deadline.IsZero() && !p.clock.Now().Before(deadline) {
+			return ErrWriteTimeout
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-p.clock.After(busyPollInterval):
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package thermalprinter
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestGPIOLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "value")
+	l := GPIOLine{Path: path}
+	for _, tt := range []struct {
+		value string
+		busy  bool
+		ok    bool
+	}{
+		{"0\n", false, true},
+		{"1\n", true, true},
+		{"x\n", false, false},
+	} {
+		if err := os.WriteFile(path, []byte(tt.value), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		busy, err := l.Busy()
+		if busy != tt.busy || (err == nil) != tt.ok {
+			t.Errorf("Busy with %q = %v, %v", tt.value, busy, err)
+		}
+	}
+}
+
+func TestWaitNotBusyTimeout(t *testing.T) {
+	p, _, clock := newTestPrinter(t)
+	p.busyLine = BusyLineFunc(func() (bool, error) { return true, nil })
+	start := clock.Now()
+	err := p.waitNotBusy(context.Background(), start.Add(time.Second))
+	if !errors.Is(err, ErrWriteTimeout) {
+		t.Fatalf("waitNotBusy = %v, want ErrWriteTimeout", err)
+	}
+	if !p.handshaking {
+		t.Error("handshaking not recorded")
+	}
+	// The deadline is checked against the printer clock, which only
+	// moves while waitNotBusy polls.
+	if got := clock.Now().Sub(start); got < time.Second || got > time.Second+busyPollInterval {
+		t.Errorf("timed out after %v, want 1s", got)
+	}
+}
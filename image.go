@@ -0,0 +1,209 @@
+package thermalprinter
+
+import (
+	"context"
+	"github.com/koyachi/go-thermalprinter/adjust"
+	"github.com/koyachi/go-thermalprinter/dither"
+	"image"
+	"math"
+)
+
+// Align positions an image that is narrower than the print width.
+type Align int
+
+const (
+	AlignLeft Align = iota
+	AlignCenter
+	AlignRight
+)
+
+// ImageOptions controls how PrintImage converts an image to dots.
+type ImageOptions struct {
+	// Width is the printed width in dots. Zero, or anything wider
+	// than the print head, scales the image to the full print width.
+	// The height follows the image's aspect ratio.
+	Width int
+	Align Align
+	// Adjust and Dither override the printer's ImageProfile for this
+	// image when they are set.
+	Adjust adjust.Filter
+	Dither dither.Ditherer
+}
+
+// ImageProfile describes how images are prepared for a particular
+// printer and paper. Adjust runs on the scaled grayscale image before
+// Dither reduces it to black and white; a nil Adjust leaves the image
+// as it is and a nil Dither means dither.Atkinson.
+type ImageProfile struct {
+	Adjust adjust.Filter
+	Dither dither.Ditherer
+}
+
+// PhotoProfile suits photographs on the CSN-A2 with standard paper. It
+// stretches the tonal range, sharpens edges and lightens the midtones
+// to offset the paper's dot gain before error diffusion.
+var PhotoProfile = ImageProfile{
+	Adjust: adjust.Pipeline{
+		adjust.AutoLevels(0.005),
+		adjust.UnsharpMask(1, 0.6, 2),
+		adjust.DotGain(0.2),
+	},
+	Dither: dither.FloydSteinberg,
+}
+
+// WithImageProfile sets how PrintImage prepares images. By default
+// images are only dithered, with dither.Atkinson.
+func WithImageProfile(prof ImageProfile) Option {
+	return func(p *Printer) {
+		p.imageProfile = prof
+	}
+}
+
+// PrintImage scales img to the print width (or opts.Width), converts
+// it to grayscale, dithers it to black and white and prints it as a
+// bitmap. Transparent pixels are treated as white paper.
+func (p *Printer) PrintImage(img image.Image, opts ImageOptions) error {
+	return p.PrintImageContext(context.Background(), img, opts)
+}
+
+// PrintImageContext is PrintImage with cancellation, see
+// PrintBitmapContext.
+func (p *Printer) PrintImageContext(ctx context.Context, img image.Image, opts ImageOptions) error {
+	prof := p.imageProfile
+	if opts.Adjust != nil {
+		prof.Adjust = opts.Adjust
+	}
+	if opts.Dither != nil {
+		prof.Dither = opts.Dither
+	}
+	bitmap, w, h := imageBitmap(img, p.printWidth, opts, prof)
+	if h == 0 {
+		return nil
+	}
+	return p.PrintBitmapContext(ctx, w, h, bitmap)
+}
+
+// imageBitmap converts img into rows packed the way PrintBitmap
+// expects them: MSB first, a set bit for a black dot. Rows are only as
+// wide as the aligned image needs, so left aligned images send no
+// padding at all.
+func imageBitmap(img image.Image, printWidth int, opts ImageOptions, prof ImageProfile) (bitmap []byte, w int, h int) {
+	b := img.Bounds()
+	if b.Empty() || printWidth <= 0 {
+		return nil, 0, 0
+	}
+	iw := opts.Width
+	if iw <= 0 || iw > printWidth {
+		iw = printWidth
+	}
+	h = (b.Dy()*iw + b.Dx()/2) / b.Dx()
+	if h < 1 {
+		h = 1
+	}
+
+	g := scaleGray(img, iw, h)
+	if prof.Adjust != nil {
+		prof.Adjust.Apply(g)
+	}
+	d := prof.Dither
+	if d == nil {
+		d = dither.Atkinson
+	}
+	d.Dither(g)
+
+	offset := 0
+	switch opts.Align {
+	case AlignCenter:
+		offset = (printWidth - iw) / 2
+	case AlignRight:
+		offset = printWidth - iw
+	}
+	w = offset + iw
+	return packBits(g, offset, w), w, h
+}
+
+// scaleGray resamples img to w x h grayscale pixels. Each output pixel
+// is the area-weighted average of the source pixels it covers, which
+// keeps fine detail from aliasing when photos are scaled down. Source
+// rows are scaled to w as they are read, so a large photo is never
+// held at full size.
+func scaleGray(img image.Image, w, h int) *image.Gray {
+	b := img.Bounds()
+	sw, sh := b.Dx(), b.Dy()
+	xTaps := areaTaps(sw, w)
+	row := make([]float64, sw)
+	tmp := make([]float64, w*sh)
+	for y := 0; y < sh; y++ {
+		for x := range row {
+			row[x] = luminance(img, b.Min.X+x, b.Min.Y+y)
+		}
+		out := tmp[y*w : (y+1)*w]
+		for x, taps := range xTaps {
+			v := 0.0
+			for _, t := range taps {
+				v += row[t.i] * t.w
+			}
+			out[x] = v
+		}
+	}
+
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	for y, taps := range areaTaps(sh, h) {
+		for x := 0; x < w; x++ {
+			v := 0.0
+			for _, t := range taps {
+				v += tmp[t.i*w+x] * t.w
+			}
+			g.Pix[y*g.Stride+x] = uint8(math.Max(0, math.Min(255, math.Round(v))))
+		}
+	}
+	return g
+}
+
+// luminance returns the brightness of the pixel at (x, y) in 0-255,
+// composited over white.
+func luminance(img image.Image, x, y int) float64 {
+	r, g, b, a := img.At(x, y).RGBA()
+	white := float64(0xffff - a)
+	return (0.299*(float64(r)+white) + 0.587*(float64(g)+white) + 0.114*(float64(b)+white)) / 257
+}
+
+type tap struct {
+	i int
+	w float64
+}
+
+// areaTaps returns, for each of m output samples, the input samples
+// among n that it overlaps and their weights.
+func areaTaps(n, m int) [][]tap {
+	taps := make([][]tap, m)
+	scale := float64(n) / float64(m)
+	for j := range taps {
+		lo, hi := float64(j)*scale, float64(j+1)*scale
+		for i := int(lo); i < n && float64(i) < hi; i++ {
+			w := math.Min(hi, float64(i+1)) - math.Max(lo, float64(i))
+			if w > 0 {
+				taps[j] = append(taps[j], tap{i, w / scale})
+			}
+		}
+	}
+	return taps
+}
+
+// packBits packs the black pixels of g into rows of w dots, starting
+// offset dots from the left edge.
+func packBits(g *image.Gray, offset, w int) []byte {
+	gw, gh := g.Rect.Dx(), g.Rect.Dy()
+	rowBytes := (w + 7) / 8
+	bitmap := make([]byte, rowBytes*gh)
+	for y := 0; y < gh; y++ {
+		row := bitmap[y*rowBytes : (y+1)*rowBytes]
+		for x := 0; x < gw; x++ {
+			if g.Pix[y*g.Stride+x] < 128 {
+				dot := offset + x
+				row[dot/8] |= 0x80 >> uint(dot%8)
+			}
+		}
+	}
+	return bitmap
+}
@@ -0,0 +1,147 @@
+package thermalprinter
+
+import (
+	"bytes"
+	"github.com/koyachi/go-thermalprinter/dither"
+	"image"
+	"image/color"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+var threshold = ImageProfile{Dither: dither.Threshold(128)}
+
+func TestImageBitmapSize(t *testing.T) {
+	tests := []struct {
+		name  string
+		w, h  int
+		opts  ImageOptions
+		wantW int
+		wantH int
+	}{
+		{"full width", 100, 50, ImageOptions{}, 384, 192},
+		{"width", 100, 50, ImageOptions{Width: 200}, 200, 100},
+		{"rounded height", 3, 2, ImageOptions{}, 384, 256},
+		{"tall", 10, 1000, ImageOptions{Width: 8}, 8, 800},
+		{"at least a row", 1000, 1, ImageOptions{Width: 100}, 100, 1},
+		{"wider than the head", 100, 50, ImageOptions{Width: 1000}, 384, 192},
+		{"negative width", 100, 50, ImageOptions{Width: -5}, 384, 192},
+		{"center", 10, 10, ImageOptions{Width: 8, Align: AlignCenter}, 188 + 8, 8},
+		{"right", 10, 10, ImageOptions{Width: 8, Align: AlignRight}, 384, 8},
+		{"empty", 0, 0, ImageOptions{}, 0, 0},
+	}
+	for _, tt := range tests {
+		img := solid(tt.w, tt.h, color.Black)
+		bitmap, w, h := imageBitmap(img, 384, tt.opts, threshold)
+		if w != tt.wantW || h != tt.wantH {
+			t.Errorf("%s: %dx%d, want %dx%d", tt.name, w, h, tt.wantW, tt.wantH)
+		}
+		if len(bitmap) != (w+7)/8*h {
+			t.Errorf("%s: %d bytes for %dx%d", tt.name, len(bitmap), w, h)
+		}
+	}
+}
+
+func TestImageBitmapBits(t *testing.T) {
+	// Black at dots 0 and 9 of a 16 dot row: MSB first, set for black.
+	img := solid(16, 1, color.White)
+	img.Set(0, 0, color.Black)
+	img.Set(9, 0, color.Black)
+	bitmap, _, _ := imageBitmap(img, 384, ImageOptions{Width: 16}, threshold)
+	if want := []byte{0x80, 0x40}; !bytes.Equal(bitmap, want) {
+		t.Errorf("bitmap = % x, want % x", bitmap, want)
+	}
+
+	// The same row centred starts 184 dots in, on a byte boundary,
+	// right aligned 368 dots in.
+	bitmap, _, _ = imageBitmap(img, 384, ImageOptions{Width: 16, Align: AlignCenter}, threshold)
+	if want := append(make([]byte, 23), 0x80, 0x40); !bytes.Equal(bitmap, want) {
+		t.Errorf("centred bitmap = % x, want % x", bitmap, want)
+	}
+	bitmap, _, _ = imageBitmap(img, 384, ImageOptions{Width: 16, Align: AlignRight}, threshold)
+	if want := append(make([]byte, 46), 0x80, 0x40); !bytes.Equal(bitmap, want) {
+		t.Errorf("right aligned bitmap = % x, want % x", bitmap, want)
+	}
+
+	// Off the byte boundary: 3 dots in, the first black dot is bit 4.
+	img = solid(2, 1, color.Black)
+	bitmap, _, _ = imageBitmap(img, 8, ImageOptions{Width: 2, Align: AlignCenter}, threshold)
+	if want := []byte{0x18}; !bytes.Equal(bitmap, want) {
+		t.Errorf("bitmap 3 dots in = % x, want % x", bitmap, want)
+	}
+}
+
+func TestScaleGray(t *testing.T) {
+	tests := []struct {
+		name string
+		img  image.Image
+		w, h int
+		want []uint8
+	}{
+		{"average", pixels(2, 1, 0, 254), 1, 1, []uint8{127}},
+		{"checkerboard", pixels(4, 2, 0, 254, 0, 254, 254, 0, 254, 0), 2, 1, []uint8{127, 127}},
+		{"same size", pixels(3, 1, 10, 20, 30), 3, 1, []uint8{10, 20, 30}},
+		{"thirds", pixels(3, 1, 0, 90, 180), 2, 1, []uint8{30, 150}},
+		{"upscale", pixels(2, 1, 0, 200), 4, 2, []uint8{0, 0, 200, 200, 0, 0, 200, 200}},
+		{"transparent", solid(2, 2, color.NRGBA{0, 0, 0, 0}), 1, 1, []uint8{255}},
+		{"half transparent", solid(2, 2, color.NRGBA{0, 0, 0, 0x80}), 1, 1, []uint8{127}},
+		{"offset bounds", pixels(2, 1, 0, 255).SubImage(image.Rect(1, 0, 2, 1)), 2, 1, []uint8{255, 255}},
+	}
+	for _, tt := range tests {
+		g := scaleGray(tt.img, tt.w, tt.h)
+		if g.Rect != image.Rect(0, 0, tt.w, tt.h) || !bytes.Equal(g.Pix, tt.want) {
+			t.Errorf("%s: %v %v, want %v", tt.name, g.Rect, g.Pix, tt.want)
+		}
+	}
+}
+
+func pixels(w, h int, v ...uint8) *image.Gray {
+	g := image.NewGray(image.Rect(0, 0, w, h))
+	copy(g.Pix, v)
+	return g
+}
+
+func TestPrintImage(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	// A transparent image with a black square in the middle.
+	img := solid(16, 16, color.Transparent)
+	for y := 4; y < 12; y++ {
+		for x := 4; x < 12; x++ {
+			img.Set(x, y, color.Black)
+		}
+	}
+	if err := p.PrintImage(img, ImageOptions{Width: 32, Align: AlignRight, Dither: dither.Threshold(128)}); err != nil {
+		t.Fatal(err)
+	}
+	out := r.Image()
+	if got := out.Bounds().Dy(); got != 32 {
+		t.Fatalf("printed %d rows, want 32", got)
+	}
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 384; x++ {
+			black := y >= 8 && y < 24 && x >= 352+8 && x < 352+24
+			if got := color.GrayModel.Convert(out.At(x, y)).(color.Gray).Y == 0; got != black {
+				t.Fatalf("dot (%d, %d) black = %v, want %v", x, y, got, black)
+			}
+		}
+	}
+}
+
+func TestPrintImageEmpty(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	if err := p.PrintImage(image.NewGray(image.Rect(5, 5, 5, 9)), ImageOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.writes) != 0 {
+		t.Errorf("sent %v for an empty image", r.writes)
+	}
+}
@@ -0,0 +1,131 @@
+package thermalprinter
+
+import (
+	"context"
+	"github.com/koyachi/go-thermalprinter/escpos"
+)
+
+// Job is a recorded sequence of printer operations. Run puts the
+// printer into its default state before the first operation and again
+// after the last, so a job neither inherits formatting from whatever
+// ran before it nor leaves any behind. A Job can be run any number of
+// times and is typically handed to a Queue:
+//
+//	job := NewJob().BoldOn().Println("TOTAL").BoldOff()
+//	err := queue.Do(ctx, job.Run)
+type Job struct {
+	steps []func(context.Context, *Printer) error
+}
+
+func NewJob() *Job {
+	return &Job{}
+}
+
+// Do appends an arbitrary operation to the job.
+func (j *Job) Do(fn func(context.Context, *Printer) error) *Job {
+	j.steps = append(j.steps, fn)
+	return j
+}
+
+func (j *Job) do(fn func(*Printer) error) *Job {
+	return j.Do(func(_ context.Context, p *Printer) error { return fn(p) })
+}
+
+func (j *Job) Print(s string) *Job {
+	return j.Do(func(ctx context.Context, p *Printer) error { return p.PrintContext(ctx, s) })
+}
+
+func (j *Job) Println(s string) *Job {
+	return j.Print(s + "\n")
+}
+
+func (j *Job) PrintBarcode(text string, barcodeType int) *Job {
+	return j.do(func(p *Printer) error { return p.PrintBarcode(text, barcodeType) })
+}
+
+func (j *Job) PrintBitmap(w int, h int, bitmap []byte) *Job {
+	return j.Do(func(ctx context.Context, p *Printer) error {
+		return p.PrintBitmapContext(ctx, w, h, bitmap)
+	})
+}
+
+func (j *Job) SetBarcodeHeight(val ...int) *Job {
+	return j.do(func(p *Printer) error { return p.SetBarcodeHeight(val...) })
+}
+
+func (j *Job) Normal() *Job          { return j.do((*Printer).Normal) }
+func (j *Job) InverseOn() *Job       { return j.do((*Printer).InverseOn) }
+func (j *Job) InverseOff() *Job      { return j.do((*Printer).InverseOff) }
+func (j *Job) UpsideDownOn() *Job    { return j.do((*Printer).UpsideDownOn) }
+func (j *Job) UpsideDownOff() *Job   { return j.do((*Printer).UpsideDownOff) }
+func (j *Job) DoubleHeightOn() *Job  { return j.do((*Printer).DoubleHeightOn) }
+func (j *Job) DoubleHeightOff() *Job { return j.do((*Printer).DoubleHeightOff) }
+func (j *Job) DoubleWidthOn() *Job   { return j.do((*Printer).DoubleWidthOn) }
+func (j *Job) DoubleWidthOff() *Job  { return j.do((*Printer).DoubleWidthOff) }
+func (j *Job) StrikeOn() *Job        { return j.do((*Printer).StrikeOn) }
+func (j *Job) StrikeOff() *Job       { return j.do((*Printer).StrikeOff) }
+func (j *Job) BoldOn() *Job          { return j.do((*Printer).BoldOn) }
+func (j *Job) BoldOff() *Job         { return j.do((*Printer).BoldOff) }
+func (j *Job) UnderlineOff() *Job    { return j.do((*Printer).UnderlineOff) }
+
+func (j *Job) UnderlineOn(weight ...int) *Job {
+	return j.do(func(p *Printer) error { return p.UnderlineOn(weight...) })
+}
+
+func (j *Job) Justify(value string) *Job {
+	return j.do(func(p *Printer) error { return p.Justify(value) })
+}
+
+func (j *Job) SetSize(value string) *Job {
+	return j.do(func(p *Printer) error { return p.SetSize(value) })
+}
+
+func (j *Job) SetLineHeight(val ...int) *Job {
+	return j.do(func(p *Printer) error { return p.SetLineHeight(val...) })
+}
+
+func (j *Job) Feed(x ...int) *Job {
+	return j.do(func(p *Printer) error { return p.Feed(x...) })
+}
+
+func (j *Job) FeedRows(rows int) *Job {
+	return j.do(func(p *Printer) error { return p.FeedRows(rows) })
+}
+
+// Run executes the job on p. The printer is returned to its default
+// state afterwards even when an operation fails; the first error is
+// reported. Operations stop early once ctx is done.
+func (j *Job) Run(ctx context.Context, p *Printer) error {
+	if err := p.restoreDefaults(); err != nil {
+		return err
+	}
+	var err error
+	for _, step := range j.steps {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		if err = step(ctx, p); err != nil {
+			break
+		}
+	}
+	if rerr := p.restoreDefaults(); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// restoreDefaults clears every print mode bit, including the ones
+// SetDefault leaves alone, and then applies SetDefault. The character
+// size is reset without the line feed SetSize adds, so running a job
+// never moves the paper by itself.
+func (p *Printer) restoreDefaults() error {
+	if err := p.Normal(); err != nil {
+		return err
+	}
+	if err := p.setDefaultFormat(); err != nil {
+		return err
+	}
+	p.charHeight = 24
+	p.maxColumn = 32
+	return p.writeBytes(escpos.CharSize(0))
+}
@@ -0,0 +1,37 @@
+package thermalprinter
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestJobRestoresDefaultsWithoutFeeding(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	rows := r.Image().Bounds().Dy()
+	job := NewJob().SetSize("L").BoldOn().DoubleWidthOn()
+	if err := job.Run(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	if p.printMode != 0 || p.charHeight != 24 || p.maxColumn != 32 {
+		t.Errorf("after Run printMode = %#x, charHeight = %d, maxColumn = %d, want defaults",
+			p.printMode, p.charHeight, p.maxColumn)
+	}
+
+	// The line feed SetSize("L") adds is the job's own; restoring the
+	// defaults around it must not feed any more paper.
+	feeds := r.Image().Bounds().Dy() - rows
+	r.writes = nil
+	if err := NewJob().Run(context.Background(), p); err != nil {
+		t.Fatal(err)
+	}
+	if got := r.Image().Bounds().Dy() - rows; got != feeds {
+		t.Errorf("empty job fed %d rows", got-feeds)
+	}
+	for _, w := range r.writes {
+		if strings.Contains(w.data, "\n") {
+			t.Errorf("empty job sent a line feed in %q", w.data)
+			break
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package thermalprinter
+
+import (
+	"context"
+	"errors"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"log/slog"
+)
+
+// WithLogger sends a trace of printer activity to l. Every write to
+// the transport is logged at debug level with the commands it carries
+// and its length, and failed writes at warn level. Nothing is logged
+// unless a logger is given.
+func WithLogger(l *slog.Logger) Option {
+	return func(p *Printer) {
+		p.logger = l
+	}
+}
+
+func (p *Printer) logEnabled(ctx context.Context, level slog.Level) bool {
+	return p.logger != nil && p.logger.Enabled(ctx, level)
+}
+
+func (p *Printer) logDebug(ctx context.Context, msg string, args ...any) {
+	if p.logEnabled(ctx, slog.LevelDebug) {
+		p.logger.DebugContext(ctx, msg, args...)
+	}
+}
+
+// traceWrite logs a write of data to the transport.
+func (p *Printer) traceWrite(ctx context.Context, data []byte, err error) {
+	level := slog.LevelDebug
+	msg := "write"
+	if err != nil {
+		level = slog.LevelWarn
+		msg = "write failed"
+	}
+	if !p.logEnabled(ctx, level) {
+		return
+	}
+	attrs := []slog.Attr{
+		slog.Any("commands", commandNames(data)),
+		slog.Int("bytes", len(data)),
+	}
+	if err != nil {
+		attrs = append(attrs, slog.Any("err", err))
+	}
+	p.logger.LogAttrs(ctx, level, msg, attrs...)
+}
+
+// commandNames lists the commands in data. A command cut short at the
+// end of the write is shown as "partial", except for the lone ESC sent
+// by Wake.
+func commandNames(data []byte) []string {
+	cmds, err := escpos.Parse(data)
+	names := make([]string, 0, len(cmds)+1)
+	for _, cmd := range cmds {
+		names = append(names, cmd.Op.String())
+	}
+	var serr *escpos.SyntaxError
+	switch {
+	case err == nil:
+	case errors.As(err, &serr) && serr.Offset == len(data)-1 && data[serr.Offset] == escpos.ESC:
+		names = append(names, escpos.OpWakeESC.String())
+	default:
+		names = append(names, "partial")
+	}
+	return names
+}
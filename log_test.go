@@ -0,0 +1,164 @@
+package thermalprinter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/koyachi/go-thermalprinter/emulator"
+	"log/slog"
+	"sync"
+	"testing"
+)
+
+// logRecord is a log record with its attributes formatted.
+type logRecord struct {
+	level slog.Level
+	msg   string
+	attrs map[string]string
+}
+
+// recordHandler keeps the records at or above its level.
+type recordHandler struct {
+	level slog.Level
+
+	mu      sync.Mutex
+	records []logRecord
+}
+
+func (h *recordHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+func (h *recordHandler) Handle(_ context.Context, r slog.Record) error {
+	rec := logRecord{level: r.Level, msg: r.Message, attrs: make(map[string]string)}
+	r.Attrs(func(a slog.Attr) bool {
+		rec.attrs[a.Key] = fmt.Sprint(a.Value.Any())
+		return true
+	})
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, rec)
+	return nil
+}
+
+func (h *recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+func (h *recordHandler) WithGroup(string) slog.Handler      { return h }
+
+// brokenTransport is an emulator whose writes fail once broken is set.
+type brokenTransport struct {
+	*emulator.Emulator
+	broken bool
+}
+
+var errUnplugged = errors.New("unplugged")
+
+func (b *brokenTransport) Write(data []byte) (int, error) {
+	if b.broken {
+		return 0, errUnplugged
+	}
+	return b.Emulator.Write(data)
+}
+
+func newLogPrinter(t *testing.T, level slog.Level) (*Printer, *recordHandler, *brokenTransport) {
+	t.Helper()
+	h := &recordHandler{level: level}
+	b := &brokenTransport{Emulator: emulator.New()}
+	p, err := NewPrinterWithTransport(b, 19200, 0, WithClock(testClock()), WithLogger(slog.New(h)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	h.records = nil
+	return p, h, b
+}
+
+func TestLogOffByDefault(t *testing.T) {
+	p, _, _ := newTestPrinter(t)
+	if p.logger != nil || p.logEnabled(context.Background(), slog.LevelError) {
+		t.Error("logging is on without WithLogger")
+	}
+	// The trace must cope with having nowhere to go.
+	if err := p.Println("quiet"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	p, h, _ := newLogPrinter(t, slog.LevelInfo)
+	if err := p.Println("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.records) != 0 {
+		t.Errorf("debug records logged at info level: %v", h.records)
+	}
+}
+
+func TestLogWrites(t *testing.T) {
+	p, h, _ := newLogPrinter(t, slog.LevelDebug)
+	if err := p.Println("hello"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.PrintBitmap(16, 2, []byte{0xff, 0xff, 0x80, 0x00}); err != nil {
+		t.Fatal(err)
+	}
+	want := []logRecord{
+		{slog.LevelDebug, "write", map[string]string{"commands": "[text LF]", "bytes": "6"}},
+		{slog.LevelDebug, "bitmap chunk", map[string]string{"row": "0", "rows": "2", "rowBytes": "2"}},
+		{slog.LevelDebug, "write", map[string]string{"commands": "[DC2 *]", "bytes": "8"}},
+	}
+	if fmt.Sprint(h.records) != fmt.Sprint(want) {
+		t.Errorf("records = %v, want %v", h.records, want)
+	}
+}
+
+func TestLogFailedWrite(t *testing.T) {
+	p, h, b := newLogPrinter(t, slog.LevelWarn)
+	b.broken = true
+	if err := p.Println("hello"); !errors.Is(err, errUnplugged) {
+		t.Fatalf("Println = %v, want %v", err, errUnplugged)
+	}
+	want := []logRecord{
+		{slog.LevelWarn, "write failed", map[string]string{"commands": "[text LF]", "bytes": "6", "err": "unplugged"}},
+	}
+	if fmt.Sprint(h.records) != fmt.Sprint(want) {
+		t.Errorf("records = %v, want %v", h.records, want)
+	}
+}
+
+func TestLogWake(t *testing.T) {
+	p, h, _ := newLogPrinter(t, slog.LevelDebug)
+	if err := p.Wake(); err != nil {
+		t.Fatal(err)
+	}
+	if len(h.records) != 11 {
+		t.Fatalf("%d records for Wake, want 11", len(h.records))
+	}
+	if got := h.records[0].attrs["commands"]; got != "[0xFF]" {
+		t.Errorf("wake byte logged as %s", got)
+	}
+	for _, r := range h.records[1:] {
+		if got := r.attrs["commands"]; got != "[ESC]" {
+			t.Errorf("lone ESC logged as %s", got)
+		}
+	}
+}
+
+func TestCommandNames(t *testing.T) {
+	tests := []struct {
+		data []byte
+		want string
+	}{
+		{[]byte("hi\n"), "[text LF]"},
+		{[]byte{0x1b}, "[ESC]"},
+		{[]byte{'a', 0x1b}, "[text ESC]"},
+		{[]byte{0x1b, 0x1b}, "[ESC ESC]"},
+		{[]byte{0x1b, '!'}, "[partial]"},
+		{[]byte{'a', 0x1d}, "[text partial]"},
+		{[]byte{0x12, '*', 2, 1, 0xff}, "[partial]"},
+		{nil, "[]"},
+	}
+	for _, tt := range tests {
+		if got := fmt.Sprint(commandNames(tt.data)); got != tt.want {
+			t.Errorf("commandNames(% x) = %s, want %s", tt.data, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package thermalprinter
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// Modem status bits usable as a busy line.
+const (
+	ModemCTS = syscall.TIOCM_CTS
+	ModemDSR = syscall.TIOCM_DSR
+	ModemCD  = syscall.TIOCM_CAR
+	ModemRI  = syscall.TIOCM_RNG
+)
+
+// ModemLine reads a busy line wired to a modem status input of a
+// serial port. The printer's DTR is usually wired to CTS, so the
+// printer is busy while the input is not asserted.
+type ModemLine struct {
+	f   *os.File
+	bit int
+}
+
+// OpenModemLine opens the serial device name to read the given modem
+// status bit. It does not disturb another handle used for data.
+func OpenModemLine(name string, bit int) (*ModemLine, error) {
+	f, err := os.OpenFile(name, os.O_RDONLY|syscall.O_NOCTTY|syscall.O_NONBLOCK, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &ModemLine{f: f, bit: bit}, nil
+}
+
+func (m *ModemLine) Busy() (bool, error) {
+	var status int32
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, m.f.Fd(), syscall.TIOCMGET, uintptr(unsafe.Pointer(&status)))
+	if errno != 0 {
+		return false, errno
+	}
+	return int(status)&m.bit == 0, nil
+}
+
+func (m *ModemLine) Close() error {
+	return m.f.Close()
+}
@@ -0,0 +1,34 @@
+//go:build !linux
+// +build !linux
+
+package thermalprinter
+
+import (
+	"errors"
+)
+
+// Modem status bits usable as a busy line.
+const (
+	ModemCTS = 0x020
+	ModemDSR = 0x100
+	ModemCD  = 0x040
+	ModemRI  = 0x080
+)
+
+var errModemLineUnsupported = errors.New("thermalprinter: modem status lines are only supported on linux")
+
+// ModemLine reads a busy line wired to a modem status input of a
+// serial port. It is only implemented on linux.
+type ModemLine struct{}
+
+func OpenModemLine(name string, bit int) (*ModemLine, error) {
+	return nil, errModemLineUnsupported
+}
+
+func (m *ModemLine) Busy() (bool, error) {
+	return false, errModemLineUnsupported
+}
+
+func (m *ModemLine) Close() error {
+	return nil
+}
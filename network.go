@@ -0,0 +1,159 @@
+package thermalprinter
+
+import (
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// DefaultNetworkPort is the raw printing port of Ethernet ESC/POS printers.
+const DefaultNetworkPort = "9100"
+
+const networkDialTimeout = 5 * time.Second
+
+// NetworkTransport is a Transport over a raw TCP connection. A broken
+// connection is dialed again on the next Write or Read.
+type NetworkTransport struct {
+	mu            sync.Mutex
+	addr          string
+	conn          net.Conn
+	closed        bool
+	readDeadline  time.Time
+	writeDeadline time.Time
+}
+
+// DialNetwork connects to a printer at addr. The port defaults to
+// DefaultNetworkPort when addr has none.
+func DialNetwork(addr string) (*NetworkTransport, error) {
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(addr, DefaultNetworkPort)
+	}
+	n := &NetworkTransport{addr: addr}
+	if _, err := n.connect(); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+// NewNetworkPrinter connects to the printer at addr and initializes it.
+// timeout is the number of seconds a single write may block.
+func NewNetworkPrinter(addr string, timeout int, opts ...Option) (*Printer, error) {
+	n, err := DialNetwork(addr)
+	if err != nil {
+		return nil, err
+	}
+	p, err := NewPrinterWithTransport(n, virtualBaud, timeout, opts...)
+	if err != nil {
+		n.Close()
+		return nil, err
+	}
+	return p, nil
+}
+
+// connect returns the current connection, dialing when there is none.
+// The caller must hold n.mu, except during DialNetwork.
+func (n *NetworkTransport) connect() (net.Conn, error) {
+	if n.closed {
+		return nil, ErrClosed
+	}
+	if n.conn != nil {
+		return n.conn, nil
+	}
+	conn, err := net.DialTimeout("tcp", n.addr, networkDialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	n.conn = conn
+	return conn, nil
+}
+
+// drop forgets a connection that failed so that it is dialed again.
+func (n *NetworkTransport) drop() {
+	if n.conn != nil {
+		n.conn.Close()
+		n.conn = nil
+	}
+}
+
+// Write sends data. When the connection turns out to be broken before
+// anything was sent it is dialed again and the write retried once.
+func (n *NetworkTransport) Write(data []byte) (int, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	for attempt := 0; ; attempt++ {
+		conn, err := n.connect()
+		if err != nil {
+			return 0, err
+		}
+		if err := conn.SetWriteDeadline(n.writeDeadline); err != nil {
+			return 0, err
+		}
+		written, err := conn.Write(data)
+		if err == nil || isTimeout(err) {
+			return written, err
+		}
+		n.drop()
+		if written > 0 || attempt > 0 {
+			return written, err
+		}
+	}
+}
+
+// Read receives status replies. It does not dial: while there is no
+// connection it returns io.EOF, as when the printer hung up.
+func (n *NetworkTransport) Read(data []byte) (int, error) {
+	n.mu.Lock()
+	conn, closed, deadline := n.conn, n.closed, n.readDeadline
+	n.mu.Unlock()
+	if closed {
+		return 0, ErrClosed
+	}
+	if conn == nil {
+		return 0, io.EOF
+	}
+	if err := conn.SetReadDeadline(deadline); err != nil {
+		return 0, err
+	}
+	read, err := conn.Read(data)
+	if err != nil && !isTimeout(err) {
+		n.mu.Lock()
+		if n.conn == conn {
+			n.drop()
+		}
+		n.mu.Unlock()
+	}
+	return read, err
+}
+
+func (n *NetworkTransport) SetWriteDeadline(t time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.writeDeadline = t
+	return nil
+}
+
+func (n *NetworkTransport) SetReadDeadline(t time.Time) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.readDeadline = t
+	if n.conn != nil {
+		return n.conn.SetReadDeadline(t)
+	}
+	return nil
+}
+
+func (n *NetworkTransport) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.closed {
+		return ErrClosed
+	}
+	n.closed = true
+	if n.conn == nil {
+		return nil
+	}
+	err := n.conn.Close()
+	n.conn = nil
+	return err
+}
@@ -0,0 +1,168 @@
+package thermalprinter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func listen(t *testing.T) net.Listener {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+	return l
+}
+
+func TestNetworkRedialsAfterHangUp(t *testing.T) {
+	l := listen(t)
+	conns := make(chan net.Conn)
+	go func() {
+		for {
+			c, err := l.Accept()
+			if err != nil {
+				close(conns)
+				return
+			}
+			conns <- c
+		}
+	}()
+
+	n, err := DialNetwork(l.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer n.Close()
+	first := <-conns
+	if _, err := n.Write([]byte("a")); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1)
+	if _, err := io.ReadFull(first, buf); err != nil || buf[0] != 'a' {
+		t.Fatalf("first connection read %q, %v", buf, err)
+	}
+	first.Close()
+	time.Sleep(50 * time.Millisecond)
+
+	// The first write after the hang-up may still be accepted by the
+	// local stack; the one after that finds the connection reset and
+	// must go out on a new one.
+	var second net.Conn
+	for i := 0; i < 10 && second == nil; i++ {
+		if _, err := n.Write([]byte("b")); err != nil {
+			t.Fatalf("write %d: %v", i, err)
+		}
+		select {
+		case second = <-conns:
+		case <-time.After(50 * time.Millisecond):
+		}
+	}
+	if second == nil {
+		t.Fatal("transport did not dial again")
+	}
+	defer second.Close()
+	second.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := io.ReadFull(second, buf); err != nil || buf[0] != 'b' {
+		t.Fatalf("second connection read %q, %v", buf, err)
+	}
+}
+
+// Transport deadlines are on the system clock whatever clock paces
+// the printer.
+var testClocks = []struct {
+	name  string
+	clock func() Clock
+}{
+	{"system", func() Clock { return realClock{} }},
+	{"fake", func() Clock { return &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)} }},
+}
+
+func TestNetworkWriteTimeout(t *testing.T) {
+	for _, tc := range testClocks {
+		t.Run(tc.name, func(t *testing.T) {
+			l := listen(t)
+			accepted := make(chan net.Conn, 1)
+			go func() {
+				// Accept but never read, so the socket buffers fill up.
+				c, err := l.Accept()
+				if err == nil {
+					accepted <- c
+				}
+			}()
+
+			p, err := NewNetworkPrinter(l.Addr().String(), 1, WithClock(tc.clock()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.port.Close()
+			defer (<-accepted).Close()
+			start := time.Now()
+			err = p.portWrite(context.Background(), make([]byte, 64<<20))
+			if !errors.Is(err, ErrWriteTimeout) {
+				t.Fatalf("portWrite = %v, want ErrWriteTimeout", err)
+			}
+			if elapsed := time.Since(start); elapsed < time.Second || elapsed > 5*time.Second {
+				t.Errorf("timed out after %v, want about 1s", elapsed)
+			}
+		})
+	}
+}
+
+// answerStatus accepts one connection and answers every DLE EOT query
+// on it with an all-clear status byte.
+func answerStatus(l net.Listener) {
+	c, err := l.Accept()
+	if err != nil {
+		return
+	}
+	defer c.Close()
+	var stream []byte
+	buf := make([]byte, 256)
+	for {
+		n, err := c.Read(buf)
+		if err != nil {
+			return
+		}
+		stream = append(stream, buf[:n]...)
+		for {
+			i := bytes.Index(stream, []byte{0x10, 0x04})
+			if i < 0 || i+2 >= len(stream) {
+				break
+			}
+			stream = stream[i+3:]
+			if _, err := c.Write([]byte{0x12}); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func TestNetworkStatus(t *testing.T) {
+	for _, tc := range testClocks {
+		t.Run(tc.name, func(t *testing.T) {
+			l := listen(t)
+			go answerStatus(l)
+			p, err := NewNetworkPrinter(l.Addr().String(), 1, WithClock(tc.clock()))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer p.Close()
+			if err := p.Println("hello"); err != nil {
+				t.Fatal(err)
+			}
+			st, err := p.Status()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := (Status{PaperPresent: true, Online: true, Complete: true}); st != want {
+				t.Errorf("Status = %+v, want %+v", st, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package thermalprinter
+
+// Option configures a Printer when it is created.
+type Option func(*Printer)
+
+// WithClock paces output with c instead of the system clock.
+func WithClock(c Clock) Option {
+	return func(p *Printer) {
+		p.clock = c
+	}
+}
+
+// defaultPrintWidth is the number of dots across the CSN-A2 print head.
+const defaultPrintWidth = 384
+
+// WithPrintWidth sets the number of dots across the print head for
+// models wider or narrower than the CSN-A2. Bitmap rows are clipped to
+// this width and PrintImage scales to it.
+func WithPrintWidth(dots int) Option {
+	return func(p *Printer) {
+		p.printWidth = dots
+	}
+}
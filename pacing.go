@@ -0,0 +1,124 @@
+package thermalprinter
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the time source that paces output to the printer.
+// Tests can substitute a fake to run without real delays.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// until returns a channel that fires at deadline on the printer's
+// clock, or nil (never fires) for the zero deadline.
+func (p *Printer) until(deadline time.Time) <-chan time.Time {
+	if deadline.IsZero() {
+		return nil
+	}
+	return p.clock.After(deadline.Sub(p.clock.Now()))
+}
+
+// wallTime converts t on the printer's clock to the system clock.
+// Deadlines handed to transports end up with the OS, which only knows
+// the system clock, whatever clock paces the output.
+func (p *Printer) wallTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Now().Add(t.Sub(p.clock.Now()))
+}
+
+// clockTime converts t on the system clock, such as a context
+// deadline, to the printer's clock.
+func (p *Printer) clockTime(t time.Time) time.Time {
+	return p.clock.Now().Add(time.Until(t))
+}
+
+func seconds(s float64) time.Duration {
+	return time.Duration(float64(time.Second) * s)
+}
+
+// timeoutSet marks the printer busy for the given number of seconds
+// from now.
+func (p *Printer) timeoutSet(second float64) {
+	p.resumeTime = p.clock.Now().Add(seconds(second))
+}
+
+// timeoutWait sleeps until the printer is ready for more data.
+func (p *Printer) timeoutWait(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	d := p.resumeTime.Sub(p.clock.Now())
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-p.clock.After(d):
+		return nil
+	}
+}
+
+// send waits until the printer is ready, writes data in one go and
+// marks the printer busy for the given number of seconds. With flow
+// control or a working busy line the printer tells when it is busy,
+// so no time is reserved.
+func (p *Printer) send(ctx context.Context, data []byte, second float64) error {
+	if err := p.timeoutWait(ctx); err != nil {
+		return err
+	}
+	if err := p.portWrite(ctx, data); err != nil {
+		return err
+	}
+	if p.flow == nil && !p.handshaking {
+		p.timeoutSet(second)
+	}
+	return nil
+}
+
+// sendPieces is send for data the printer may need to pause partway
+// through. When it can say that it is busy, by XOFF or the busy line,
+// each piece is a separate write so that a signal arriving in the
+// middle holds back the pieces after it; otherwise the pieces are
+// joined into one write.
+func (p *Printer) sendPieces(ctx context.Context, pieces [][]byte, second float64) error {
+	if p.flow == nil && p.busyLine == nil {
+		var data []byte
+		for _, piece := range pieces {
+			data = append(data, piece...)
+		}
+		return p.send(ctx, data, second)
+	}
+	if err := p.timeoutWait(ctx); err != nil {
+		return err
+	}
+	// The pieces are traced together, as the command they make up.
+	var sent []byte
+	for _, piece := range pieces {
+		sent = append(sent, piece...)
+		if err := p.transportWrite(ctx, piece); err != nil {
+			p.traceWrite(ctx, sent, err)
+			return err
+		}
+	}
+	p.traceWrite(ctx, sent, nil)
+	if p.flow == nil && !p.handshaking {
+		p.timeoutSet(second)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+package thermalprinter
+
+import (
+	"context"
+	"github.com/koyachi/go-thermalprinter/emulator"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock moves time forward only when something waits on it.
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+type sentWrite struct {
+	at   time.Time
+	data string
+}
+
+// recorder passes writes to an emulator and notes when each was made.
+type recorder struct {
+	*emulator.Emulator
+	clock  Clock
+	writes []sentWrite
+}
+
+func (r *recorder) Write(data []byte) (int, error) {
+	r.writes = append(r.writes, sentWrite{r.clock.Now(), string(data)})
+	return r.Emulator.Write(data)
+}
+
+func newTestPrinter(t *testing.T) (*Printer, *recorder, *fakeClock) {
+	t.Helper()
+	clock := &fakeClock{now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)}
+	r := &recorder{Emulator: emulator.New(), clock: clock}
+	p, err := NewPrinterWithTransport(r, 19200, 0, WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.writes = nil
+	return p, r, clock
+}
+
+func TestWriteBatchesLines(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	long := strings.Repeat("a", 40)
+	if err := p.Print("hello\n\nworld\n" + long); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"hello\n", "\n", "world\n", long[:33], long[33:]}
+	if len(r.writes) != len(want) {
+		t.Fatalf("got %d writes %v, want %d", len(r.writes), r.writes, len(want))
+	}
+	for i, w := range want {
+		if r.writes[i].data != w {
+			t.Errorf("write %d = %q, want %q", i, r.writes[i].data, w)
+		}
+	}
+	// The character that overflows the line wraps it, like a newline.
+	if p.column != len(long)-33 {
+		t.Errorf("column = %d, want %d", p.column, len(long)-33)
+	}
+	if d := r.Diagnostics(); len(d) != 0 {
+		t.Errorf("emulator diagnostics: %v", d)
+	}
+}
+
+func TestWriteDelays(t *testing.T) {
+	p, r, clock := newTestPrinter(t)
+	// Let the power-up and reset delays pass.
+	if err := p.timeoutWait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	start := clock.Now()
+	if err := p.Print("hello\n\nx"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	textLine := 6*p.byteTime + 24*p.dotPrintTime + 8*p.dotFeedTime
+	blankLine := p.byteTime + 32*p.dotFeedTime
+	partial := p.byteTime
+	wantAt := []float64{0, textLine, textLine + blankLine}
+	if len(r.writes) != len(wantAt) {
+		t.Fatalf("got %d writes %v, want %d", len(r.writes), r.writes, len(wantAt))
+	}
+	for i, at := range wantAt {
+		assertDuration(t, "write "+r.writes[i].data, r.writes[i].at.Sub(start), seconds(at))
+	}
+	// Close waits until the last byte has been printed.
+	assertDuration(t, "total", clock.Now().Sub(start), seconds(textLine+blankLine+partial))
+}
+
+func assertDuration(t *testing.T, what string, got, want time.Duration) {
+	t.Helper()
+	if diff := got - want; diff < -time.Microsecond || diff > time.Microsecond {
+		t.Errorf("%s at %v, want %v", what, got, want)
+	}
+}
+
+func TestWriteDeadlineFromContext(t *testing.T) {
+	p, _, clock := newTestPrinter(t)
+	p.timeout = 10
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// The context deadline is on the system clock, the write deadline
+	// on the printer's.
+	if d := p.writeDeadline(ctx).Sub(clock.Now()); d <= 0 || d > 50*time.Millisecond {
+		t.Errorf("write deadline %v from now, want the context's 50ms", d)
+	}
+	if d := p.writeDeadline(context.Background()).Sub(clock.Now()); d != 10*time.Second {
+		t.Errorf("write deadline %v from now, want the 10s timeout", d)
+	}
+}
@@ -0,0 +1,117 @@
+package thermalprinter
+
+import (
+	"context"
+	"sync"
+)
+
+// Queue makes a Printer safe to share between goroutines. Jobs are
+// run one at a time, in the order they were submitted, by a single
+// goroutine that owns the Printer; the bytes of two jobs are never
+// interleaved. Once a Queue is created the Printer must only be used
+// from within jobs.
+type Queue struct {
+	p *Printer
+
+	mu      sync.Mutex
+	pending []*queuedJob
+	closed  bool
+
+	wake chan struct{}
+	done chan struct{}
+}
+
+type queuedJob struct {
+	ctx    context.Context
+	fn     func(context.Context, *Printer) error
+	result chan error
+}
+
+// NewQueue starts the goroutine that runs the jobs submitted for p.
+func NewQueue(p *Printer) *Queue {
+	q := &Queue{
+		p:    p,
+		wake: make(chan struct{}, 1),
+		done: make(chan struct{}),
+	}
+	go q.run()
+	return q
+}
+
+// Submit adds a job to the queue and returns a channel that receives
+// its result when it has run. A job whose ctx is done before it starts
+// is skipped and gets ctx.Err().
+func (q *Queue) Submit(ctx context.Context, fn func(context.Context, *Printer) error) <-chan error {
+	result := make(chan error, 1)
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		result <- ErrClosed
+		return result
+	}
+	q.pending = append(q.pending, &queuedJob{ctx: ctx, fn: fn, result: result})
+	q.mu.Unlock()
+	q.signal()
+	return result
+}
+
+// Do submits a job and waits for its result, or until ctx is done. In
+// that case Do returns ctx.Err() at once: a job still in the queue is
+// skipped when its turn comes, and one that is running sees its ctx
+// done.
+func (q *Queue) Do(ctx context.Context, fn func(context.Context, *Printer) error) error {
+	select {
+	case err := <-q.Submit(ctx, fn):
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting jobs and waits until the queued ones have run.
+// It does not close the Printer.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	if q.closed {
+		q.mu.Unlock()
+		return ErrClosed
+	}
+	q.closed = true
+	q.mu.Unlock()
+	q.signal()
+	<-q.done
+	return nil
+}
+
+func (q *Queue) signal() {
+	select {
+	case q.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (q *Queue) run() {
+	defer close(q.done)
+	for {
+		q.mu.Lock()
+		if len(q.pending) == 0 {
+			closed := q.closed
+			q.mu.Unlock()
+			if closed {
+				return
+			}
+			<-q.wake
+			continue
+		}
+		j := q.pending[0]
+		q.pending[0] = nil
+		q.pending = q.pending[1:]
+		q.mu.Unlock()
+
+		if err := j.ctx.Err(); err != nil {
+			j.result <- err
+			continue
+		}
+		j.result <- j.fn(j.ctx, q.p)
+	}
+}
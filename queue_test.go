@@ -0,0 +1,132 @@
+package thermalprinter
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// blockQueue starts a queue whose first job runs until release is
+// closed.
+func blockQueue(t *testing.T) (q *Queue, release chan struct{}) {
+	t.Helper()
+	p, _, _ := newTestPrinter(t)
+	q = NewQueue(p)
+	release = make(chan struct{})
+	started := make(chan struct{})
+	q.Submit(context.Background(), func(context.Context, *Printer) error {
+		close(started)
+		<-release
+		return nil
+	})
+	<-started
+	return q, release
+}
+
+func TestQueueRunsInOrder(t *testing.T) {
+	q, release := blockQueue(t)
+	var order []int
+	var results []<-chan error
+	for i := 0; i < 5; i++ {
+		i := i
+		results = append(results, q.Submit(context.Background(), func(_ context.Context, p *Printer) error {
+			order = append(order, i)
+			return p.Print("x\n")
+		}))
+	}
+	close(release)
+	for i, result := range results {
+		if err := <-result; err != nil {
+			t.Errorf("job %d: %v", i, err)
+		}
+	}
+	for i, n := range order {
+		if n != i {
+			t.Fatalf("jobs ran in order %v", order)
+		}
+	}
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestQueueSkipsCancelledJob(t *testing.T) {
+	q, release := blockQueue(t)
+	ran := false
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := q.Do(ctx, func(context.Context, *Printer) error {
+		ran = true
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Do = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Do returned after %v while the job was queued", elapsed)
+	}
+
+	close(release)
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if ran {
+		t.Error("cancelled job ran")
+	}
+}
+
+func TestQueueCloseDrains(t *testing.T) {
+	q, release := blockQueue(t)
+	ran := 0
+	var results []<-chan error
+	for i := 0; i < 3; i++ {
+		results = append(results, q.Submit(context.Background(), func(context.Context, *Printer) error {
+			ran++
+			return nil
+		}))
+	}
+
+	closed := make(chan error)
+	go func() { closed <- q.Close() }()
+	select {
+	case <-closed:
+		t.Fatal("Close returned while jobs were queued")
+	case <-time.After(20 * time.Millisecond):
+	}
+	close(release)
+	if err := <-closed; err != nil {
+		t.Fatal(err)
+	}
+	if ran != 3 {
+		t.Errorf("%d of 3 queued jobs ran before Close returned", ran)
+	}
+	for _, result := range results {
+		if err := <-result; err != nil {
+			t.Error(err)
+		}
+	}
+}
+
+func TestQueueSubmitAfterClose(t *testing.T) {
+	p, _, _ := newTestPrinter(t)
+	q := NewQueue(p)
+	if err := q.Close(); err != nil {
+		t.Fatal(err)
+	}
+	job := func(context.Context, *Printer) error {
+		t.Error("job ran after Close")
+		return nil
+	}
+	if err := <-q.Submit(context.Background(), job); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Close = %v, want ErrClosed", err)
+	}
+	if err := q.Do(context.Background(), job); !errors.Is(err, ErrClosed) {
+		t.Errorf("Do after Close = %v, want ErrClosed", err)
+	}
+	if err := q.Close(); !errors.Is(err, ErrClosed) {
+		t.Errorf("second Close = %v, want ErrClosed", err)
+	}
+}
@@ -0,0 +1,73 @@
+package thermalprinter
+
+import (
+	"context"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"io"
+)
+
+// Replay sends a recorded ESC/POS stream, such as a capture or a .prn
+// file from other software, to the printer. The stream is sent command
+// by command and paced with the same timing model as the print methods,
+// so it does not overflow the printer's buffer.
+func (p *Printer) Replay(ctx context.Context, r io.Reader) error {
+	if p.closed {
+		return ErrClosed
+	}
+	s := escpos.NewScanner(r)
+	for s.Scan() {
+		if err := p.replay(ctx, s.Command()); err != nil {
+			return err
+		}
+	}
+	return s.Err()
+}
+
+// replay sends cmd and tracks its effect on the printer state.
+func (p *Printer) replay(ctx context.Context, cmd escpos.Command) error {
+	d := float64(len(cmd.Raw)) * p.byteTime
+	switch cmd.Op {
+	case escpos.OpText, escpos.OpLF:
+		// Offline is not tracked here; the printer drops the text
+		// itself, as it did when the stream was recorded.
+		return p.replayText(ctx, cmd.Raw)
+	case escpos.OpReset:
+		p.resetState()
+	case escpos.OpWakeESC:
+		d = 0.1
+	case escpos.OpPrintMode:
+		p.printMode = cmd.Params[0]
+		p.updateCharMetrics()
+	case escpos.OpCharSize:
+		// Low nibble is the height multiplier, high nibble the width
+		size := cmd.Params[0]
+		p.charHeight = 24 * (int(size&0x0f) + 1)
+		p.maxColumn = 32 / (int(size>>4) + 1)
+	case escpos.OpLineSpacing:
+		p.lineSpacing = int(cmd.Params[0]) - 24
+	case escpos.OpDefaultLineSpacing:
+		p.lineSpacing = 8
+	case escpos.OpBarcodeHeight:
+		p.barcodeHeight = int(cmd.Params[0])
+	case escpos.OpBarcode:
+		d += float64(p.barcodeHeight+40) * p.dotPrintTime
+		p.prevByte = newlineByte()
+	case escpos.OpBitmap:
+		d = p.chunkTime(bitmapChunk{rows: int(cmd.Params[0]), width: int(cmd.Params[1])})
+		p.prevByte = newlineByte()
+	case escpos.OpFeedRows:
+		d += float64(cmd.Params[0]) * p.dotFeedTime
+	case escpos.OpFeedLines:
+		d += float64(int(cmd.Params[0])*(p.charHeight+p.lineSpacing)) * p.dotFeedTime
+	}
+	return p.send(ctx, cmd.Raw, d)
+}
+
+// replayText sends text through write with offline checks disabled.
+func (p *Printer) replayText(ctx context.Context, text []byte) error {
+	offline := p.offline
+	p.offline = false
+	defer func() { p.offline = offline }()
+	_, err := p.write(ctx, text)
+	return err
+}
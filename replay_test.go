@@ -0,0 +1,108 @@
+package thermalprinter
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/koyachi/go-thermalprinter/emulator"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"testing"
+)
+
+func TestReplayCapture(t *testing.T) {
+	var capture bytes.Buffer
+	e := emulator.New()
+	p, err := NewPrinterWithTransport(NewCaptureTransport(e, &capture, nil), 19200, 0, WithClock(testClock()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job := []func() error{
+		func() error { return p.Println("Hello") },
+		p.DoubleHeightOn,
+		func() error { return p.Println("Tall") },
+		p.DoubleHeightOff,
+		func() error { return p.Justify("C") },
+		func() error { return p.SetSize("L") },
+		func() error { return p.Println("Large") },
+		func() error { return p.SetSize("S") },
+		func() error { return p.PrintBarcode("ABC", CODE39) },
+		func() error { return p.PrintBitmap(16, 3, []byte{0xff, 0xff, 0, 0, 0x81, 0x81}) },
+		func() error { return p.Feed(2) },
+	}
+	for i, step := range job {
+		if err := step(); err != nil {
+			t.Fatalf("step %d: %v", i, err)
+		}
+	}
+
+	q, r, _ := newTestPrinter(t)
+	if err := q.Replay(context.Background(), &capture); err != nil {
+		t.Fatal(err)
+	}
+	if e.Image().Bounds().Dy() == 0 {
+		t.Fatal("the job printed nothing")
+	}
+	if !sameImage(r.Image(), e.Image()) {
+		t.Error("replayed job prints differently")
+	}
+	if d := r.Diagnostics(); len(d) != 0 {
+		t.Errorf("emulator diagnostics: %v", d)
+	}
+}
+
+func TestReplayPacing(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	bt, pt, ft := p.byteTime, p.dotPrintTime, p.dotFeedTime
+	steps := []struct {
+		cmd   []byte
+		delay float64
+	}{
+		{[]byte("ab"), 2 * bt},
+		// A line of text is printed, a blank one fed.
+		{[]byte{escpos.LF}, bt + 24*pt + 8*ft},
+		{[]byte{escpos.LF}, bt + 32*ft},
+		{escpos.Bitmap(2, 48, make([]byte, 96)), (4+96)*bt + 2*pt},
+		{escpos.BarcodeWithLength(4, "AB"), 6*bt + (50+40)*pt},
+		{escpos.BarcodeHeight(100), 3 * bt},
+		{escpos.BarcodeWithLength(4, "AB"), 6*bt + (100+40)*pt},
+		{escpos.FeedRows(10), 3*bt + 10*ft},
+		{escpos.FeedLines(2), 3*bt + 2*32*ft},
+		{escpos.CharSize(0x01), 3 * bt},
+		{escpos.FeedLines(1), 3*bt + 56*ft},
+		{escpos.LineSpacing(40), 3 * bt},
+		{escpos.FeedLines(1), 3*bt + 64*ft},
+		{escpos.WakeESC(), 0.1},
+		{escpos.Reset(), 2 * bt},
+	}
+	var stream []byte
+	for _, s := range steps {
+		stream = append(stream, s.cmd...)
+	}
+	if err := p.Replay(context.Background(), bytes.NewReader(stream)); err != nil {
+		t.Fatal(err)
+	}
+	if len(r.writes) != len(steps) {
+		t.Fatalf("got %d writes %v, want %d", len(r.writes), r.writes, len(steps))
+	}
+	for i, s := range steps[:len(steps)-1] {
+		if r.writes[i].data != string(s.cmd) {
+			t.Errorf("write %d = %q, want %q", i, r.writes[i].data, s.cmd)
+		}
+		gap := r.writes[i+1].at.Sub(r.writes[i].at)
+		assertDuration(t, fmt.Sprintf("after write %d", i), gap, seconds(s.delay))
+	}
+}
+
+func TestReplayTruncated(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	err := p.Replay(context.Background(), bytes.NewReader([]byte("ab\n\x1b!")))
+	var se *escpos.SyntaxError
+	if !errors.As(err, &se) || !errors.Is(err, escpos.ErrTruncated) || se.Offset != 3 {
+		t.Fatalf("Replay = %v, want ErrTruncated at offset 3", err)
+	}
+	// What came before the truncated command is still printed.
+	if len(r.writes) != 2 {
+		t.Errorf("writes = %v, want the text and the line feed", r.writes)
+	}
+}
@@ -0,0 +1,36 @@
+package thermalprinter
+
+import (
+	"github.com/tarm/serial"
+	"time"
+)
+
+// Reads give up after this long so that status queries can time out.
+const serialReadTimeout = 100 * time.Millisecond
+
+// SerialTransport is a Transport over a serial (UART) device.
+type SerialTransport struct {
+	port *serial.Port
+}
+
+// OpenSerial opens the serial device name at the given baud rate.
+func OpenSerial(name string, baud int) (*SerialTransport, error) {
+	c := &serial.Config{Name: name, Baud: baud, ReadTimeout: serialReadTimeout}
+	s, err := serial.OpenPort(c)
+	if err != nil {
+		return nil, err
+	}
+	return &SerialTransport{port: s}, nil
+}
+
+func (s *SerialTransport) Write(data []byte) (int, error) {
+	return s.port.Write(data)
+}
+
+func (s *SerialTransport) Read(data []byte) (int, error) {
+	return s.port.Read(data)
+}
+
+func (s *SerialTransport) Close() error {
+	return s.port.Close()
+}
@@ -0,0 +1,197 @@
+package thermalprinter
+
+import (
+	"errors"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"io"
+	"time"
+)
+
+// ErrStatusUnsupported is returned by Status when the transport has
+// no return path or the printer answers none of the status queries.
+var ErrStatusUnsupported = errors.New("thermalprinter: status not supported")
+
+var errNoReply = errors.New("thermalprinter: no reply")
+
+const defaultStatusTimeout = 500 * time.Millisecond
+
+// discardTimeout bounds how long stale input is waited for before a
+// status query is sent.
+const discardTimeout = 10 * time.Millisecond
+
+// Status is the printer state as reported by the printer itself.
+type Status struct {
+	PaperPresent bool
+	PaperNearEnd bool
+	CoverOpen    bool
+	Online       bool
+
+	// Complete is false when the printer only answered the paper
+	// sensor query, in which case CoverOpen and Online are guesses.
+	Complete bool
+}
+
+// Err returns ErrPaperOut or ErrOffline when s does not allow
+// printing, and nil otherwise.
+func (s Status) Err() error {
+	switch {
+	case !s.PaperPresent:
+		return ErrPaperOut
+	case !s.Online || s.CoverOpen:
+		return ErrOffline
+	}
+	return nil
+}
+
+// readDeadliner is implemented by transports that can bound the time
+// a Read blocks. The deadline is on the system clock.
+type readDeadliner interface {
+	SetReadDeadline(t time.Time) error
+}
+
+// WithStatusTimeout sets how long Status waits for each reply.
+func WithStatusTimeout(d time.Duration) Option {
+	return func(p *Printer) {
+		p.statusTimeout = d
+	}
+}
+
+// Status asks the printer for its state. The real-time DLE EOT
+// queries are tried first; printers that ignore them are asked for
+// the paper sensor with GS r, then ESC v.
+func (p *Printer) Status() (Status, error) {
+	if p.closed {
+		return Status{}, ErrClosed
+	}
+	if _, ok := reader(p.port); !ok {
+		return Status{}, ErrStatusUnsupported
+	}
+
+	st, err := p.realTimeStatus()
+	if err != errNoReply {
+		return st, err
+	}
+
+	// Paper sensor: bits 0-1 near end, bits 2-3 paper end
+	b, err := p.query(escpos.TransmitStatus(1), nil)
+	if err == nil {
+		return Status{PaperPresent: b&0x0c == 0, PaperNearEnd: b&0x03 != 0, Online: true}, nil
+	}
+	if err != errNoReply {
+		return Status{}, err
+	}
+
+	// CSN-A2 paper status: bit 2 paper out
+	b, err = p.query(escpos.PaperStatus(), nil)
+	if err == nil {
+		return Status{PaperPresent: b&0x04 == 0, Online: true}, nil
+	}
+	if err != errNoReply {
+		return Status{}, err
+	}
+	return Status{}, ErrStatusUnsupported
+}
+
+func (p *Printer) realTimeStatus() (Status, error) {
+	var replies [3]byte
+	for i, n := range []byte{1, 2, 4} {
+		b, err := p.query(escpos.RealTimeStatus(n), isRealTimeStatus)
+		if err != nil {
+			return Status{}, err
+		}
+		replies[i] = b
+	}
+	printer, offline, paper := replies[0], replies[1], replies[2]
+	return Status{
+		PaperPresent: paper&0x60 == 0,
+		PaperNearEnd: paper&0x0c != 0,
+		CoverOpen:    offline&0x04 != 0,
+		Online:       printer&0x08 == 0,
+		Complete:     true,
+	}, nil
+}
+
+// Replies to DLE EOT have bits 1 and 4 set and bits 0 and 7 clear.
+func isRealTimeStatus(b byte) bool {
+	return b&0x93 == 0x12
+}
+
+// query sends cmd and returns the first reply byte accepted by valid
+// (any byte when valid is nil), or errNoReply after the status timeout.
+func (p *Printer) query(cmd []byte, valid func(byte) bool) (byte, error) {
+	if err := p.discardInput(); err != nil {
+		return 0, err
+	}
+	if err := p.writeBytes(cmd); err != nil {
+		return 0, err
+	}
+	deadline := p.clock.Now().Add(p.statusTimeout)
+	for {
+		b, err := p.readReply(deadline)
+		if err != nil {
+			return 0, err
+		}
+		if valid == nil || valid(b) {
+			return b, nil
+		}
+	}
+}
+
+// readReply returns the next byte sent by the printer, or errNoReply
+// at deadline.
+func (p *Printer) readReply(deadline time.Time) (byte, error) {
+	if p.flow != nil {
+		return p.flow.read(deadline)
+	}
+
+	r := p.port.(io.Reader)
+	if rd, ok := p.port.(readDeadliner); ok && rd.SetReadDeadline(p.wallTime(deadline)) == nil {
+		defer rd.SetReadDeadline(time.Time{})
+	}
+	buf := make([]byte, 1)
+	for p.clock.Now().Before(deadline) {
+		n, err := r.Read(buf)
+		if n == 1 {
+			return buf[0], nil
+		}
+		switch {
+		case err == nil:
+		case err == io.EOF:
+			// Nothing buffered; serial reads return this after
+			// their own read timeout.
+			<-p.clock.After(10 * time.Millisecond)
+		case isTimeout(err):
+			return 0, errNoReply
+		default:
+			return 0, transportError(err)
+		}
+	}
+	return 0, errNoReply
+}
+
+// discardInput drops whatever the printer has sent that nobody read,
+// such as a late reply to an earlier query, so that it is not taken
+// for the reply to the next one.
+func (p *Printer) discardInput() error {
+	if p.flow != nil {
+		p.flow.discard()
+		return nil
+	}
+
+	r := p.port.(io.Reader)
+	if rd, ok := p.port.(readDeadliner); ok && rd.SetReadDeadline(time.Now().Add(discardTimeout)) == nil {
+		defer rd.SetReadDeadline(time.Time{})
+	}
+	buf := make([]byte, 64)
+	for {
+		n, err := r.Read(buf)
+		switch {
+		case err == io.EOF || isTimeout(err):
+			return nil
+		case err != nil:
+			return transportError(err)
+		case n == 0:
+			return nil
+		}
+	}
+}
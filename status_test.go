@@ -0,0 +1,201 @@
+package thermalprinter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/koyachi/go-thermalprinter/emulator"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestStatusDiscardsStaleReplies(t *testing.T) {
+	p, r, _ := newTestPrinter(t)
+	// A paper sensor reply nobody read. It has bit 3 set, which in a
+	// reply to DLE EOT 1 would mean the printer is offline.
+	r.SetPaper(true, true)
+	if err := p.writeBytes(escpos.RealTimeStatus(4)); err != nil {
+		t.Fatal(err)
+	}
+	r.SetPaper(true, false)
+
+	st, err := p.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Status{PaperPresent: true, Online: true, Complete: true}
+	if st != want {
+		t.Errorf("Status = %+v, want %+v", st, want)
+	}
+	if n, err := r.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("%d replies left unread", n)
+	}
+}
+
+// partialTransport is an emulator that leaves the queries in ignore
+// unanswered, like printers that only implement some of them. It
+// records the queries sent.
+type partialTransport struct {
+	*emulator.Emulator
+	ignore  map[escpos.Op]bool
+	queries []escpos.Op
+}
+
+func (pt *partialTransport) Write(data []byte) (int, error) {
+	n, err := pt.Emulator.Write(data)
+	cmds, _ := escpos.Parse(data)
+	for _, cmd := range cmds {
+		switch cmd.Op {
+		case escpos.OpRealTimeStatus, escpos.OpTransmitStatus, escpos.OpPaperStatus:
+			pt.queries = append(pt.queries, cmd.Op)
+			if pt.ignore[cmd.Op] {
+				io.ReadAll(pt.Emulator)
+			}
+		}
+	}
+	return n, err
+}
+
+func TestStatusFallback(t *testing.T) {
+	const (
+		dleEOT = escpos.OpRealTimeStatus
+		gsR    = escpos.OpTransmitStatus
+		escV   = escpos.OpPaperStatus
+	)
+	tests := []struct {
+		name       string
+		ignore     []escpos.Op
+		paperOut   bool
+		nearEnd    bool
+		want       Status
+		err        error
+		queries    []escpos.Op
+		unanswered int
+	}{
+		{
+			"DLE EOT", nil, false, true,
+			Status{PaperPresent: true, PaperNearEnd: true, Online: true, Complete: true}, nil,
+			[]escpos.Op{dleEOT, dleEOT, dleEOT}, 0,
+		},
+		{
+			"GS r", []escpos.Op{dleEOT}, false, true,
+			Status{PaperPresent: true, PaperNearEnd: true, Online: true}, nil,
+			[]escpos.Op{dleEOT, gsR}, 1,
+		},
+		{
+			"GS r paper out", []escpos.Op{dleEOT}, true, false,
+			Status{Online: true}, nil,
+			[]escpos.Op{dleEOT, gsR}, 1,
+		},
+		{
+			// ESC v does not report the near end sensor.
+			"ESC v", []escpos.Op{dleEOT, gsR}, false, true,
+			Status{PaperPresent: true, Online: true}, nil,
+			[]escpos.Op{dleEOT, gsR, escV}, 2,
+		},
+		{
+			"ESC v paper out", []escpos.Op{dleEOT, gsR}, true, false,
+			Status{Online: true}, nil,
+			[]escpos.Op{dleEOT, gsR, escV}, 2,
+		},
+		{
+			"silent", []escpos.Op{dleEOT, gsR, escV}, false, false,
+			Status{}, ErrStatusUnsupported,
+			[]escpos.Op{dleEOT, gsR, escV}, 3,
+		},
+	}
+	for _, tt := range tests {
+		pt := &partialTransport{Emulator: emulator.New(), ignore: make(map[escpos.Op]bool)}
+		for _, op := range tt.ignore {
+			pt.ignore[op] = true
+		}
+		pt.SetPaper(!tt.paperOut, tt.nearEnd)
+		clock := testClock()
+		p, err := NewPrinterWithTransport(pt, 19200, 0, WithClock(clock), WithStatusTimeout(100*time.Millisecond))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.timeoutWait(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		start := clock.Now()
+		st, err := p.Status()
+		if st != tt.want || err != tt.err {
+			t.Errorf("%s: Status = %+v, %v, want %+v, %v", tt.name, st, err, tt.want, tt.err)
+		}
+		if fmt.Sprint(pt.queries) != fmt.Sprint(tt.queries) {
+			t.Errorf("%s: queries %v, want %v", tt.name, pt.queries, tt.queries)
+		}
+		// Each unanswered query waits out the status timeout.
+		elapsed := clock.Now().Sub(start)
+		if min := time.Duration(tt.unanswered) * 100 * time.Millisecond; elapsed < min || elapsed > min+10*time.Millisecond {
+			t.Errorf("%s: Status took %v, want %v", tt.name, elapsed, min)
+		}
+	}
+}
+
+// writeOnly is a transport with no return path.
+type writeOnly struct{ io.Writer }
+
+func (writeOnly) Close() error { return nil }
+
+func TestStatusUnsupported(t *testing.T) {
+	p, err := NewPrinterWithTransport(writeOnly{io.Discard}, 19200, 0, WithClock(testClock()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := p.Status(); err != ErrStatusUnsupported {
+		t.Errorf("Status = %v, want ErrStatusUnsupported", err)
+	}
+	p.Close()
+	if _, err := p.Status(); err != ErrClosed {
+		t.Errorf("Status after Close = %v, want ErrClosed", err)
+	}
+}
+
+func TestStatusReportsPrinterState(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(e *emulator.Emulator)
+		err   error
+	}{
+		{"ready", func(e *emulator.Emulator) {}, nil},
+		{"near end", func(e *emulator.Emulator) { e.SetPaper(true, true) }, nil},
+		{"paper out", func(e *emulator.Emulator) { e.SetPaper(false, false) }, ErrPaperOut},
+		{"cover open", func(e *emulator.Emulator) { e.SetCoverOpen(true) }, ErrOffline},
+		{"offline", func(e *emulator.Emulator) { e.Write(escpos.Online(false)) }, ErrOffline},
+	}
+	for _, tt := range tests {
+		p, r, _ := newTestPrinter(t)
+		tt.setup(r.Emulator)
+		st, err := p.Status()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := st.Err(); err != tt.err {
+			t.Errorf("%s: Status %+v, Err = %v, want %v", tt.name, st, err, tt.err)
+		}
+	}
+}
+
+func TestStatusErr(t *testing.T) {
+	tests := []struct {
+		st  Status
+		err error
+	}{
+		{Status{PaperPresent: true, Online: true}, nil},
+		{Status{PaperPresent: true, PaperNearEnd: true, Online: true, Complete: true}, nil},
+		{Status{Online: true}, ErrPaperOut},
+		// Paper out wins over offline.
+		{Status{CoverOpen: true}, ErrPaperOut},
+		{Status{PaperPresent: true}, ErrOffline},
+		{Status{PaperPresent: true, Online: true, CoverOpen: true}, ErrOffline},
+	}
+	for _, tt := range tests {
+		if err := tt.st.Err(); !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+			t.Errorf("%+v.Err() = %v, want %v", tt.st, err, tt.err)
+		}
+	}
+}
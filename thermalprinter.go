@@ -1,8 +1,10 @@
 package thermalprinter
 
 import (
-	"fmt"
-	"github.com/tarm/serial"
+	"context"
+	"github.com/koyachi/go-thermalprinter/escpos"
+	"io"
+	"log/slog"
 	"strings"
 	"time"
 	"unicode/utf8"
@@ -33,8 +35,10 @@ const (
 )
 
 type Printer struct {
-	port            *serial.Port
+	port            Transport
+	clock           Clock
 	timeout         int
+	statusTimeout   time.Duration
 	resumeTime      time.Time
 	byteTime        float64
 	dotPrintTime    float64
@@ -47,6 +51,18 @@ type Printer struct {
 	barcodeHeight   int
 	printMode       byte
 	defaultHeatTime int
+	offline         bool
+	closed          bool
+	sleepOnClose    bool
+	flowControl     bool
+	flow            *flow
+	busyLine        BusyLine
+	handshaking     bool
+	pendingWrite    chan struct{}
+	bitmapProgress  func(BitmapProgress)
+	logger          *slog.Logger
+	printWidth      int
+	imageProfile    ImageProfile
 }
 
 func charToByte(c string) byte {
@@ -58,15 +74,30 @@ func newlineByte() byte {
 	return charToByte("\n")
 }
 
-func NewPrinter(name string, baud int, timeout int) (*Printer, error) {
-	c := &serial.Config{Name: name, Baud: baud}
-	s, err := serial.OpenPort(c)
+// NewPrinter opens the serial device name at the given baud rate
+// and initializes the printer attached to it.
+func NewPrinter(name string, baud int, timeout int, opts ...Option) (*Printer, error) {
+	s, err := OpenSerial(name, baud)
 	if err != nil {
 		return nil, err
 	}
+	p, err := NewPrinterWithTransport(s, baud, timeout, opts...)
+	if err != nil {
+		s.Close()
+		return nil, err
+	}
+	return p, nil
+}
 
+// NewPrinterWithTransport initializes a printer that is driven through t.
+// baud is used to estimate how long each byte takes to reach the printer.
+// timeout is the number of seconds a single write may block before it
+// fails with ErrWriteTimeout; 0 disables the limit.
+func NewPrinterWithTransport(t Transport, baud int, timeout int, opts ...Option) (*Printer, error) {
 	p := &Printer{
-		port:            s,
+		port:            t,
+		clock:           realClock{},
+		statusTimeout:   defaultStatusTimeout,
 		timeout:         timeout,
 		dotPrintTime:    0.033,
 		dotFeedTime:     0.0025,
@@ -78,6 +109,17 @@ func NewPrinter(name string, baud int, timeout int) (*Printer, error) {
 		barcodeHeight:   50,
 		printMode:       0,
 		defaultHeatTime: 60,
+		printWidth:      defaultPrintWidth,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	if p.flowControl {
+		r, ok := reader(t)
+		if !ok {
+			return nil, ErrFlowControlUnsupported
+		}
+		p.flow = newFlow(r, p.clock)
 	}
 
 	// Calculate time to issue one byte to the printer.
@@ -92,8 +134,14 @@ func NewPrinter(name string, baud int, timeout int) (*Printer, error) {
 	// receive data.
 	p.timeoutSet(0.5)
 
-	p.Wake()
-	p.Reset()
+	if err := p.Wake(); err != nil {
+		p.stopFlow()
+		return nil, err
+	}
+	if err := p.Reset(); err != nil {
+		p.stopFlow()
+		return nil, err
+	}
 
 	//これがあると薄い？ => 関係無かった
 	/*
@@ -151,105 +199,283 @@ func NewPrinter(name string, baud int, timeout int) (*Printer, error) {
 	return p, nil
 }
 
-func (p *Printer) timeoutSet(second float64) {
-	d := time.Duration(float64(time.Second) * second)
-	p.resumeTime = time.Now().Add(d)
+// printable reports why print commands cannot be issued right now.
+func (p *Printer) printable() error {
+	if p.closed {
+		return ErrClosed
+	}
+	if p.offline {
+		return ErrOffline
+	}
+	return nil
+}
+
+// writeDeadliner is implemented by transports that can bound the
+// time a Write blocks, such as network connections. As for net.Conn,
+// the deadline is on the system clock.
+type writeDeadliner interface {
+	SetWriteDeadline(t time.Time) error
 }
 
-func (p *Printer) timeoutWait() {
-	for time.Now().Sub(p.resumeTime) < 0 {
-		time.Sleep(100 * time.Millisecond)
+// writeDeadline returns the time on the printer's clock the next
+// write must complete by, or the zero time when there is no limit.
+func (p *Printer) writeDeadline(ctx context.Context) time.Time {
+	var deadline time.Time
+	if p.timeout > 0 {
+		deadline = p.clock.Now().Add(time.Duration(p.timeout) * time.Second)
 	}
+	if d, ok := ctx.Deadline(); ok {
+		if d = p.clockTime(d); deadline.IsZero() || d.Before(deadline) {
+			deadline = d
+		}
+	}
+	return deadline
 }
 
-func (p *Printer) writeBytes(data []byte) error {
-	p.timeoutWait()
-	p.timeoutSet(float64(len(data)) * p.byteTime)
-	_, err := p.port.Write(data)
-	if err != nil {
+// portWrite sends data to the transport as is.
+func (p *Printer) portWrite(ctx context.Context, data []byte) error {
+	err := p.transportWrite(ctx, data)
+	p.traceWrite(ctx, data, err)
+	return err
+}
+
+// transportWrite is portWrite without the trace.
+func (p *Printer) transportWrite(ctx context.Context, data []byte) error {
+	if p.closed {
+		return ErrClosed
+	}
+	var n int
+	var err error
+	deadline := p.writeDeadline(ctx)
+	if p.flow != nil {
+		if err := p.flow.wait(ctx, deadline); err != nil {
+			return err
+		}
+	}
+	if p.busyLine != nil {
+		if err := p.waitNotBusy(ctx, deadline); err != nil {
+			return err
+		}
+	}
+	if err := p.waitPendingWrite(ctx, deadline); err != nil {
 		return err
 	}
+	if p.setWriteDeadline(deadline) {
+		n, err = p.port.Write(data)
+	} else if deadline.IsZero() && ctx.Done() == nil {
+		n, err = p.port.Write(data)
+	} else {
+		n, err = p.writeAsync(ctx, data, deadline)
+	}
+	if err == nil && n < len(data) {
+		err = io.ErrShortWrite
+	}
+	return transportError(err)
+}
+
+// setWriteDeadline passes deadline on to the transport and reports
+// whether the transport enforces it.
+func (p *Printer) setWriteDeadline(deadline time.Time) bool {
+	dw, ok := p.port.(writeDeadliner)
+	return ok && dw.SetWriteDeadline(p.wallTime(deadline)) == nil
+}
+
+// writeAsync enforces deadline on transports that cannot do it
+// themselves. A write that times out is abandoned, not interrupted:
+// it is left to finish in the background and the next write waits for
+// it, so the two never reach the port at the same time.
+func (p *Printer) writeAsync(ctx context.Context, data []byte, deadline time.Time) (int, error) {
+	type result struct {
+		n   int
+		err error
+	}
+	done := make(chan result, 1)
+	finished := make(chan struct{})
+	go func() {
+		n, err := p.port.Write(data)
+		done <- result{n, err}
+		close(finished)
+	}()
+
+	var err error
+	select {
+	case r := <-done:
+		return r.n, r.err
+	case <-p.until(deadline):
+		err = ErrWriteTimeout
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	// The write may have completed at the same moment.
+	select {
+	case r := <-done:
+		return r.n, r.err
+	default:
+	}
+	p.pendingWrite = finished
+	return 0, err
+}
 
-	return nil
+// waitPendingWrite waits for a write abandoned by writeAsync to finish
+// before another one is started.
+func (p *Printer) waitPendingWrite(ctx context.Context, deadline time.Time) error {
+	if p.pendingWrite == nil {
+		return nil
+	}
+	select {
+	case <-p.pendingWrite:
+		p.pendingWrite = nil
+		return nil
+	case <-p.until(deadline):
+		return ErrWriteTimeout
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Printer) writeBytes(data []byte) error {
+	return p.writeBytesContext(context.Background(), data)
+}
+
+func (p *Printer) writeBytesContext(ctx context.Context, data []byte) error {
+	if p.closed {
+		return ErrClosed
+	}
+	return p.send(ctx, data, float64(len(data))*p.byteTime)
 }
 
-func (p *Printer) write(data []byte) error {
-	for _, c := range data {
-		if c == 0x13 {
+// write sends text. Each line (up to a newline or a wrap) goes out
+// in a single port write, followed by the time it takes to print.
+// It returns how many bytes of data were sent before any error.
+func (p *Printer) write(ctx context.Context, data []byte) (int, error) {
+	if err := p.printable(); err != nil {
+		return 0, err
+	}
+	column, prevByte := p.column, p.prevByte
+	line := make([]byte, 0, p.maxColumn+1)
+	d := 0.0
+	n := 0
+	for i, c := range data {
+		if c == xoff {
+			// Never send the flow control stop byte as text.
 			continue
 		}
-
-		p.timeoutWait()
-		_, err := p.port.Write([]byte{c})
-		if err != nil {
-			return err
+		line = append(line, c)
+		t, eol := p.advance(c)
+		d += t
+		if !eol {
+			continue
 		}
-		d := p.byteTime
-		if c == newlineByte() || p.column == p.maxColumn {
-			// Newline or wrap
-			if p.prevByte == newlineByte() {
-				// Feed line (blank)
-				d += float64(p.charHeight+p.lineSpacing) * p.dotFeedTime
-			} else {
-				// Text line
-				d += (float64(p.charHeight) * p.dotPrintTime) + (float64(p.lineSpacing) * p.dotFeedTime)
-				p.column = 0
-				// Treat wrap as Newlineon next pass
-				c = newlineByte()
-			}
-		} else {
-			p.column += 1
+		if err := p.send(ctx, line, d); err != nil {
+			p.column, p.prevByte = column, prevByte
+			return n, err
 		}
-		p.timeoutSet(d)
-		p.prevByte = c
+		n = i + 1
+		column, prevByte = p.column, p.prevByte
+		line = line[:0]
+		d = 0
 	}
-	/*
-		// TODO:n
-		_, err := p.port.Write(data)
-		if err != nil {
-			return err
+	if len(line) > 0 {
+		if err := p.send(ctx, line, d); err != nil {
+			p.column, p.prevByte = column, prevByte
+			return n, err
 		}
-	*/
-	return nil
+	}
+	return len(data), nil
+}
+
+// advance updates the column tracking for text byte c and returns
+// the seconds it takes to transfer and print it, and whether it
+// ended a line.
+func (p *Printer) advance(c byte) (float64, bool) {
+	d := p.byteTime
+	eol := false
+	if c == newlineByte() || p.column == p.maxColumn {
+		// Newline or wrap
+		eol = true
+		if p.prevByte == newlineByte() {
+			// Feed line (blank)
+			d += float64(p.charHeight+p.lineSpacing) * p.dotFeedTime
+		} else {
+			// Text line
+			d += (float64(p.charHeight) * p.dotPrintTime) + (float64(p.lineSpacing) * p.dotFeedTime)
+			p.column = 0
+			// Treat wrap as Newlineon next pass
+			c = newlineByte()
+		}
+	} else {
+		p.column += 1
+	}
+	p.prevByte = c
+	return d, eol
+}
+
+func (p *Printer) Reset() error {
+	p.resetState()
+	return p.writeBytes(escpos.Reset())
 }
 
-func (p *Printer) Reset() {
+// resetState mirrors what ESC @ does to the printer.
+func (p *Printer) resetState() {
 	p.prevByte = newlineByte() // Treat as  if prior line is blank
 	p.column = 0
 	p.maxColumn = 32
 	p.charHeight = 24
 	p.lineSpacing = 8
 	p.barcodeHeight = 50
-	p.writeBytes([]byte{27, 64})
-}
-
-func (p *Printer) SetDefault() {
-	p.Online()
-	p.Justify("L")
-	p.InverseOff()
-	p.DoubleHeightOff()
-	p.SetLineHeight(32)
-	p.BoldOff()
-	p.UnderlineOff()
-	p.SetBarcodeHeight(50)
-	p.SetSize("s")
-}
-
-func (p *Printer) PrintBarcode(text string, barcodeType int) {
-	p.writeBytes([]byte{
-		29, 72, 2, // Print label below barcodeType
-		29, 119, 3, // Barcode width
-		29, 107, byte(barcodeType), // Barcode type
-	})
-	// Print string
-	p.timeoutWait()
-	p.timeoutSet(float64(p.barcodeHeight+40) * p.dotPrintTime)
-	p.port.Write([]byte(text))
+	p.offline = false
+	p.printMode = 0
+}
+
+func (p *Printer) SetDefault() error {
+	if err := p.setDefaultFormat(); err != nil {
+		return err
+	}
+	return p.SetSize("s")
+}
+
+// setDefaultFormat is SetDefault without the character size, which
+// SetSize can only change together with a line feed.
+func (p *Printer) setDefaultFormat() error {
+	steps := []func() error{
+		p.Online,
+		func() error { return p.Justify("L") },
+		p.InverseOff,
+		p.DoubleHeightOff,
+		func() error { return p.SetLineHeight(32) },
+		p.BoldOff,
+		p.UnderlineOff,
+		func() error { return p.SetBarcodeHeight(50) },
+	}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Printer) PrintBarcode(text string, barcodeType int) error {
+	if err := p.printable(); err != nil {
+		return err
+	}
+	var setup []byte
+	setup = append(setup, escpos.HRIPosition(escpos.HRIBelow)...) // Print label below barcode
+	setup = append(setup, escpos.BarcodeWidth(3)...)
+	if err := p.writeBytes(setup); err != nil {
+		return err
+	}
+	// Barcode type and string
+	data := escpos.Barcode(byte(barcodeType), text)
+	err := p.send(context.Background(), data, float64(len(data))*p.byteTime+float64(p.barcodeHeight+40)*p.dotPrintTime)
+	if err != nil {
+		return err
+	}
 	p.prevByte = newlineByte()
-	p.Feed(2)
+	return p.Feed(2)
 }
 
-func (p *Printer) SetBarcodeHeight(val ...int) {
+func (p *Printer) SetBarcodeHeight(val ...int) error {
 	_val := 50
 	if val != nil && len(val) == 1 {
 		_val = val[0]
@@ -258,31 +484,30 @@ func (p *Printer) SetBarcodeHeight(val ...int) {
 		_val = 1
 	}
 	p.barcodeHeight = _val
-	p.writeBytes([]byte{29, 104, byte(_val)})
+	return p.writeBytes(escpos.BarcodeHeight(byte(_val)))
 }
 
-func (p *Printer) writePrintMode() {
-	p.writeBytes([]byte{27, 33, p.printMode})
+func (p *Printer) writePrintMode() error {
+	return p.writeBytes(escpos.PrintMode(p.printMode))
 }
 
-func (p *Printer) setPrintMode(mask byte) {
+func (p *Printer) setPrintMode(mask byte) error {
 	p.printMode |= mask
-	p.writePrintMode()
-	if p.printMode&DoubleHeightMask != 0 {
-		p.charHeight = 48
-	} else {
-		p.charHeight = 24
-	}
-	if p.printMode&DoubleWidthMask != 0 {
-		p.maxColumn = 16
-	} else {
-		p.maxColumn = 32
-	}
+	err := p.writePrintMode()
+	p.updateCharMetrics()
+	return err
 }
 
-func (p *Printer) unsetPrintMode(mask byte) {
+func (p *Printer) unsetPrintMode(mask byte) error {
 	p.printMode &= ^mask
-	p.writePrintMode()
+	err := p.writePrintMode()
+	p.updateCharMetrics()
+	return err
+}
+
+// updateCharMetrics derives the character height and the columns per
+// line from printMode.
+func (p *Printer) updateCharMetrics() {
 	if p.printMode&DoubleHeightMask != 0 {
 		p.charHeight = 48
 	} else {
@@ -295,91 +520,99 @@ func (p *Printer) unsetPrintMode(mask byte) {
 	}
 }
 
-func (p *Printer) Normal() {
+func (p *Printer) Normal() error {
 	p.printMode = 0
-	p.writePrintMode()
+	err := p.writePrintMode()
+	p.updateCharMetrics()
+	return err
 }
 
-func (p *Printer) InverseOn() {
-	p.setPrintMode(InverseMask)
+func (p *Printer) InverseOn() error {
+	return p.setPrintMode(InverseMask)
 }
 
-func (p *Printer) InverseOff() {
-	p.unsetPrintMode(InverseMask)
+func (p *Printer) InverseOff() error {
+	return p.unsetPrintMode(InverseMask)
 }
 
-func (p *Printer) UpsideDownOn() {
-	p.setPrintMode(UpdownMask)
+func (p *Printer) UpsideDownOn() error {
+	return p.setPrintMode(UpdownMask)
 }
 
-func (p *Printer) UpsideDownOff() {
-	p.unsetPrintMode(UpdownMask)
+func (p *Printer) UpsideDownOff() error {
+	return p.unsetPrintMode(UpdownMask)
 }
 
-func (p *Printer) DoubleHeightOn() {
-	p.setPrintMode(DoubleHeightMask)
+func (p *Printer) DoubleHeightOn() error {
+	return p.setPrintMode(DoubleHeightMask)
 }
 
-func (p *Printer) DoubleHeightOff() {
-	p.unsetPrintMode(DoubleHeightMask)
+func (p *Printer) DoubleHeightOff() error {
+	return p.unsetPrintMode(DoubleHeightMask)
 }
 
-func (p *Printer) DoubleWidthOn() {
-	p.setPrintMode(DoubleWidthMask)
+func (p *Printer) DoubleWidthOn() error {
+	return p.setPrintMode(DoubleWidthMask)
 }
 
-func (p *Printer) DoubleWidthOff() {
-	p.unsetPrintMode(DoubleWidthMask)
+func (p *Printer) DoubleWidthOff() error {
+	return p.unsetPrintMode(DoubleWidthMask)
 }
 
-func (p *Printer) StrikeOn() {
-	p.setPrintMode(StrikeMask)
+func (p *Printer) StrikeOn() error {
+	return p.setPrintMode(StrikeMask)
 }
 
-func (p *Printer) StrikeOff() {
-	p.unsetPrintMode(StrikeMask)
+func (p *Printer) StrikeOff() error {
+	return p.unsetPrintMode(StrikeMask)
 }
 
-func (p *Printer) BoldOn() {
-	p.setPrintMode(BoldMask)
+func (p *Printer) BoldOn() error {
+	return p.setPrintMode(BoldMask)
 }
 
-func (p *Printer) BoldOff() {
-	p.unsetPrintMode(BoldMask)
+func (p *Printer) BoldOff() error {
+	return p.unsetPrintMode(BoldMask)
 }
 
-func (p *Printer) Justify(value string) {
+func (p *Printer) Justify(value string) error {
 	var pos byte
 	switch strings.ToUpper(value) {
 	case "C":
-		pos = 1
+		pos = escpos.JustifyCenter
 	case "R":
-		pos = 2
+		pos = escpos.JustifyRight
 	default:
-		pos = 0
+		pos = escpos.JustifyLeft
 	}
-	p.writeBytes([]byte{0x1B, 0x61, pos})
+	return p.writeBytes(escpos.Justify(pos))
 }
 
 // Feeds by the specified number of lines
-func (p *Printer) Feed(x ...int) {
+func (p *Printer) Feed(x ...int) error {
 	_x := 1
 	if x != nil && len(x) == 1 {
 		_x = x[0]
 	}
 	for _x > 0 {
-		p.Print("\n")
+		if err := p.Print("\n"); err != nil {
+			return err
+		}
 		_x -= 1
 	}
+	return nil
 }
 
 // Feed by the specified number of indivisual pixel rows
-func (p *Printer) FeedRows(rows int) {
-	p.writeBytes([]byte{27, 74, byte(rows)})
-	p.timeoutSet(float64(rows) * p.dotFeedTime)
+func (p *Printer) FeedRows(rows int) error {
+	if err := p.printable(); err != nil {
+		return err
+	}
+	data := escpos.FeedRows(byte(rows))
+	return p.send(context.Background(), data, float64(len(data))*p.byteTime+float64(rows)*p.dotFeedTime)
 }
 
-func (p *Printer) SetSize(value string) {
+func (p *Printer) SetSize(value string) error {
 	var size byte
 	switch strings.ToUpper(value) {
 	case "L":
@@ -398,11 +631,12 @@ func (p *Printer) SetSize(value string) {
 		p.charHeight = 24
 		p.maxColumn = 32
 	}
-	p.writeBytes([]byte{29, 33, size, 10})
+	err := p.writeBytes(append(escpos.CharSize(size), escpos.LF))
 	p.prevByte = newlineByte() // Setting the size adds a linefeed
+	return err
 }
 
-func (p *Printer) SetLineHeight(val ...int) {
+func (p *Printer) SetLineHeight(val ...int) error {
 	_val := 24
 	if val != nil && len(val) == 1 {
 		_val = val[0]
@@ -413,79 +647,23 @@ func (p *Printer) SetLineHeight(val ...int) {
 	// height when setting line height, making this more skin
 	// to inter-line spacing. Default line spacing is 32
 	// (char height of 24, line spacing of 8).
-	p.writeBytes([]byte{27, 51, byte(_val)})
+	return p.writeBytes(escpos.LineSpacing(byte(_val)))
 }
 
 // Underlines of different weights can be produced:
 // 0 - no underline
 // 1 - normal underline
 // 2 - thick underline
-func (p *Printer) UnderlineOn(weight ...int) {
+func (p *Printer) UnderlineOn(weight ...int) error {
 	_weight := 1
 	if weight != nil && len(weight) == 1 {
 		_weight = weight[0]
 	}
-	p.writeBytes([]byte{27, 45, byte(_weight)})
+	return p.writeBytes(escpos.Underline(byte(_weight)))
 }
 
-func (p *Printer) UnderlineOff() {
-	p.UnderlineOn(0)
-}
-
-func (p *Printer) PrintBitmap(w int, h int, bitmap []byte, lineAtATime bool) error {
-	rowBytes := int(float64(w+7) / 8) // Round up to next byte boundary
-	rowBytesClipped := 0
-	if rowBytes >= 48 {
-		rowBytesClipped = 48 // 384 pixels max width
-	} else {
-		rowBytesClipped = rowBytes
-	}
-
-	// if lineAtATime is true, print bitmaps
-	// scanline-at-a-time (rather than in chunks).
-	// This tends to make for much cleaner printin
-	// (no feed gaps) on large images...but has the
-	// oppsite effect on small images that would fit
-	// in a single 'chunk', so use carefully!
-	maxChunkHeight := 0
-	if lineAtATime {
-		maxChunkHeight = 1
-	} else {
-		// TODO:maxChunkHeightを使うロジックおかしそうなのでなおす => timeoutSetの時間調整？
-		maxChunkHeight = 255
-		//maxChunkHeight = 127
-		//maxChunkHeight = h
-	}
-
-	i := 0
-	for rowStart := 0; rowStart < h; rowStart += maxChunkHeight {
-		chunkHeight := h - rowStart
-		if chunkHeight > maxChunkHeight {
-			chunkHeight = maxChunkHeight
-		}
-		fmt.Printf("rowBytes = %d, rowBytesClipped = %d\n", rowBytes, rowBytesClipped)
-		fmt.Printf("h = %d, chunkHeight = %d, rowStart = %d\n", h, chunkHeight, rowStart)
-
-		// Timeout wait happens here
-		p.writeBytes([]byte{18, 42, byte(chunkHeight), byte(rowBytesClipped)})
-
-		for y := 0; y < chunkHeight; y++ {
-			fmt.Printf("  y = %d, i = %d\n", y, i)
-			for x := 0; x < rowBytesClipped; x++ {
-				_, err := p.port.Write([]byte{bitmap[i]})
-				if err != nil {
-					return err
-				}
-				i++
-			}
-			i += rowBytes - rowBytesClipped
-		}
-		//ためしにコメントアウト => 解決した。けどどうすべきか。
-		//p.timeoutSet(float64(chunkHeight) * p.dotPrintTime)
-	}
-	p.prevByte = newlineByte()
-
-	return nil
+func (p *Printer) UnderlineOff() error {
+	return p.UnderlineOn(0)
 }
 
 func (p *Printer) SetTimes(pt float64, ft float64) {
@@ -495,37 +673,96 @@ func (p *Printer) SetTimes(pt float64, ft float64) {
 
 // Take the printer offline. Print commands sent after this
 // will be ignored until 'online' is called.
-func (p *Printer) Offline() {
-	p.writeBytes([]byte{27, 61, 0})
+func (p *Printer) Offline() error {
+	err := p.writeBytes(escpos.Online(false))
+	if err == nil {
+		p.offline = true
+	}
+	return err
 }
 
 // Take the printer online, Subsequent print commands will be obeyed.
-func (p *Printer) Online() {
-	p.writeBytes([]byte{27, 61, 1})
+func (p *Printer) Online() error {
+	err := p.writeBytes(escpos.Online(true))
+	if err == nil {
+		p.offline = false
+	}
+	return err
 }
 
-func (p *Printer) Sleep() {
+func (p *Printer) Sleep() error {
 	seconds := 1
-	p.writeBytes([]byte{27, 56, byte(seconds)})
+	return p.writeBytes(escpos.Sleep(byte(seconds)))
 }
 
-func (p *Printer) Wake() {
+func (p *Printer) Wake() error {
 	p.timeoutSet(0)
-	p.writeBytes([]byte{255})
+	if err := p.writeBytes(escpos.Wake()); err != nil {
+		return err
+	}
 	for i := 0; i < 10; i++ {
-		p.writeBytes([]byte{27})
+		if err := p.writeBytes(escpos.WakeESC()); err != nil {
+			return err
+		}
 		p.timeoutSet(0.1)
 	}
+	return nil
+}
+
+func (p *Printer) Flush() error {
+	return p.writeBytes(escpos.Flush())
+}
+
+func (p *Printer) Print(s string) error {
+	return p.PrintContext(context.Background(), s)
+}
+
+// PrintContext is Print with cancellation. On cancellation the rest
+// of s is dropped and ctx.Err() is returned.
+func (p *Printer) PrintContext(ctx context.Context, s string) error {
+	_, err := p.write(ctx, []byte(s))
+	return err
+}
+
+func (p *Printer) Println(s string) error {
+	return p.Print(s + "\n")
+}
+
+// Write prints data as text, so a Printer can be used as an io.Writer
+// with fmt.Fprintf, io.Copy, bufio.Writer or text/tabwriter. Column
+// tracking and pacing are the same as for Print. On error n counts the
+// bytes of data that were sent in full lines before the failure.
+func (p *Printer) Write(data []byte) (n int, err error) {
+	return p.write(context.Background(), data)
 }
 
-func (p *Printer) Flush() {
-	p.writeBytes([]byte{12})
+// WriteString is Write for a string.
+func (p *Printer) WriteString(s string) (n int, err error) {
+	return p.write(context.Background(), []byte(s))
 }
 
-func (p *Printer) Print(s string) {
-	p.write([]byte(s))
+// SetSleepOnClose makes Close put the printer to sleep before
+// releasing the transport.
+func (p *Printer) SetSleepOnClose(sleep bool) {
+	p.sleepOnClose = sleep
 }
 
-func (p *Printer) Println(s string) {
-	p.Print(s + "\n")
+// Close waits until the printer has finished the data already sent,
+// optionally puts it to sleep and closes the transport. Any call
+// after Close returns ErrClosed.
+func (p *Printer) Close() error {
+	if p.closed {
+		return ErrClosed
+	}
+	var err error
+	if p.sleepOnClose {
+		err = p.Sleep()
+	}
+	p.timeoutWait(context.Background())
+	p.closed = true
+	if cerr := p.port.Close(); err == nil {
+		err = transportError(cerr)
+	}
+	p.stopFlow()
+	return err
 }
@@ -0,0 +1,22 @@
+package thermalprinter
+
+import "testing"
+
+func TestNormalResetsCharMetrics(t *testing.T) {
+	p, _, _ := newTestPrinter(t)
+	if err := p.DoubleWidthOn(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.DoubleHeightOn(); err != nil {
+		t.Fatal(err)
+	}
+	if p.maxColumn != 16 || p.charHeight != 48 {
+		t.Fatalf("double size: maxColumn = %d, charHeight = %d, want 16 and 48", p.maxColumn, p.charHeight)
+	}
+	if err := p.Normal(); err != nil {
+		t.Fatal(err)
+	}
+	if p.maxColumn != 32 || p.charHeight != 24 {
+		t.Errorf("after Normal: maxColumn = %d, charHeight = %d, want 32 and 24", p.maxColumn, p.charHeight)
+	}
+}
@@ -0,0 +1,29 @@
+package thermalprinter
+
+import (
+	"io"
+)
+
+// Transport is the byte channel between Printer and the device.
+//
+// A Transport may additionally implement io.Reader when the device
+// has a return path (status replies, flow control bytes).
+type Transport interface {
+	io.Writer
+	io.Closer
+}
+
+// reader returns the return path of t. Transports that wrap another
+// one, and so always have a Read method, implement readable to report
+// whether the wrapped transport can actually be read from.
+func reader(t Transport) (io.Reader, bool) {
+	r, ok := t.(io.Reader)
+	if w, wraps := t.(interface{ readable() bool }); ok && wraps {
+		ok = w.readable()
+	}
+	return r, ok
+}
+
+// virtualBaud is used to estimate byte times on transports that have
+// no serial line of their own.
+const virtualBaud = 115200